@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/pkg/errors"
+)
+
+// aliasFilePerson is a person in the alias file. The file is a YAML or JSON list of them,
+// see the README for an example.
+type aliasFilePerson struct {
+	Login   string   `yaml:"login" json:"login"`
+	Name    string   `yaml:"name" json:"name"`
+	Team    string   `yaml:"team" json:"team"`
+	Aliases []string `yaml:"aliases" json:"aliases"`
+}
+
+// loadAliases reads the alias file given by inputs, see readConfig. Returns nil if there isn't one.
+func loadAliases(inputs processedInputs) (app.Aliases, error) {
+	if inputs.Aliases == "" {
+		return nil, nil
+	}
+
+	var people []aliasFilePerson
+	if err := readConfig(inputs.Aliases, &people); err != nil {
+		return nil, errors.Wrap(err, "[loadAliases] could not load alias file")
+	}
+
+	res := make([]app.Person, len(people))
+	for i, p := range people {
+		res[i] = app.Person{Login: p.Login, Name: p.Name, Team: p.Team, Aliases: p.Aliases}
+	}
+	aliases, err := app.NewAliases(res)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[loadAliases] invalid alias file %s", inputs.Aliases)
+	}
+	return aliases, nil
+}
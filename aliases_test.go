@@ -0,0 +1,87 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestLoadAliases(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gh-contrib-stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, text string) string {
+		file := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(file, []byte(text), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	leslie := app.Person{Login: "Leslie-Knope", Name: "Leslie Knope", Team: "parks", Aliases: []string{"lknope-corp"}}
+
+	ts := []struct {
+		Name      string
+		File      string
+		Expect    []app.Person
+		ExpectErr string
+	}{
+		{
+			Name: "YAML",
+			File: write("people.yml", "# people with several accounts\n"+
+				"- login: Leslie-Knope\n  name: Leslie Knope\n  team: parks\n  aliases: [lknope-corp]\n"+
+				"- login: Ron-Swanson\n"),
+			Expect: []app.Person{leslie, {Login: "Ron-Swanson"}},
+		},
+		{
+			Name:   "JSON",
+			File:   write("people.json", `[{"login": "Leslie-Knope", "name": "Leslie Knope", "team": "parks", "aliases": ["lknope-corp"]}]`),
+			Expect: []app.Person{leslie},
+		},
+		{
+			Name:      "Unknown Key",
+			File:      write("typo.yml", "- login: Leslie-Knope\n  alias: [lknope-corp]\n"),
+			ExpectErr: "[loadAliases] could not load alias file: [readConfig] invalid " + filepath.Join(dir, "typo.yml"),
+		},
+		{
+			Name:      "Shared Login",
+			File:      write("shared.json", `[{"login": "Leslie-Knope"}, {"login": "Ron-Swanson", "aliases": ["leslie-knope"]}]`),
+			ExpectErr: "[loadAliases] invalid alias file " + filepath.Join(dir, "shared.json") + `: [NewAliases] login "leslie-knope" belongs to both Leslie-Knope and Ron-Swanson`,
+		},
+		{
+			Name:      "Missing File",
+			File:      filepath.Join(dir, "missing.yml"),
+			ExpectErr: "[loadAliases] could not load alias file: [readConfig] could not read file",
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			res, err := loadAliases(processedInputs{Aliases: tc.File})
+			if tc.ExpectErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.ExpectErr) {
+					t.Fatalf("loadAliases: Have `err`: %v want: %s", err, tc.ExpectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadAliases: Unexpected Error: %v", err)
+			}
+			expect, _ := app.NewAliases(tc.Expect)
+			if !reflect.DeepEqual(res, expect) {
+				t.Errorf("loadAliases:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, expect)
+			}
+		})
+	}
+
+	if res, err := loadAliases(processedInputs{}); res != nil || err != nil {
+		t.Errorf("loadAliases: have %v, %v for no alias file want nil, nil", res, err)
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+// Defaults of --coverage and --window for the analyze command
+const (
+	defaultCoverage = 50
+	defaultWindow   = 13 // about a quarter
+)
+
+// printAnalysis writes the analysis in the format given by inputs
+func printAnalysis(out io.Writer, a app.Analysis, inputs processedInputs) error {
+	switch inputs.Format {
+	case formatJSON:
+		return printAnalysisJSON(out, a)
+	case formatCSV:
+		return printAnalysisCSV(out, a, ',')
+	case formatTSV:
+		return printAnalysisCSV(out, a, '\t')
+	case formatMarkdown:
+		printAnalysisMarkdown(out, a)
+		return nil
+	default:
+		printConcentrations(out, a)
+		return nil
+	}
+}
+
+// describeBusFactor describes what the bus factor of the analysis covers
+// e.g. "the fewest contributors with 50% of the commits"
+func describeBusFactor(a app.Analysis) string {
+	return fmt.Sprintf("the fewest contributors with %s%% of the %s", strconv.FormatFloat(a.Coverage, 'f', -1, 64), a.Metric)
+}
+
+// describeTrend describes the windows of the trends of the analysis e.g. "13 week windows ending each month"
+func describeTrend(a app.Analysis) string {
+	return fmt.Sprintf("%d week windows ending each %s", a.Window, a.Interval)
+}
+
+// concentrationLine formats the concentration as tab separated fields, for the text output
+func concentrationLine(c app.Concentration, metric string) string {
+	return fmt.Sprintf("Contributors: %d\t %s: %d\t Bus factor: %d\t Gini: %.2f\t Herfindahl: %.2f",
+		c.Contributors, capitalise(metric), c.Total, c.BusFactor, c.Gini, c.Herfindahl)
+}
+
+// printConcentrations writes what the bus factor covers and a line per repo with its concentration,
+// followed by the trend of each repo, if there is one
+func printConcentrations(out io.Writer, a app.Analysis) {
+	fmt.Fprintf(out, "Bus factor: %s, %s\n", describeBusFactor(a), describeRange(a.From, a.To))
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, ra := range a.Repos {
+		fmt.Fprintf(w, "Repo: %s\t %s\n", ra.Repo, concentrationLine(ra.Concentration, a.Metric))
+	}
+	w.Flush()
+
+	for _, ra := range a.Repos {
+		if len(ra.Trend) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\nTrend of %s, %s:\n", ra.Repo, describeTrend(a))
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, wc := range ra.Trend {
+			fmt.Fprintf(w, "Window: %s\t %s\n", describeRange(wc.From, wc.To), concentrationLine(wc.Concentration, a.Metric))
+		}
+		w.Flush()
+	}
+}
+
+// jsonAnalysis is the JSON output of the analyze command, see jsonReport.
+// Interval and WindowWeeks are only set with a trend.
+type jsonAnalysis struct {
+	SchemaVersion int                `json:"schema_version"`
+	From          *time.Time         `json:"from"`
+	To            time.Time          `json:"to"`
+	GeneratedAt   time.Time          `json:"generated_at"`
+	Metric        string             `json:"metric"`
+	Coverage      float64            `json:"coverage"`
+	Interval      string             `json:"interval,omitempty"`
+	WindowWeeks   int                `json:"window_weeks,omitempty"`
+	Repos         []jsonRepoAnalysis `json:"repos"`
+}
+
+// jsonRepoAnalysis is the analysis of a repo in the JSON output. Repo is "(all repositories)" for all of them together.
+type jsonRepoAnalysis struct {
+	Repo string `json:"repo"`
+	jsonConcentration
+	Trend []jsonWindowConcentration `json:"trend,omitempty"`
+}
+
+type jsonWindowConcentration struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	jsonConcentration
+}
+
+// jsonConcentration is a concentration in the JSON output. The indexes are rounded to three decimal places.
+type jsonConcentration struct {
+	Contributors int     `json:"contributors"`
+	Total        int     `json:"total"`
+	BusFactor    int     `json:"bus_factor"`
+	Gini         float64 `json:"gini"`
+	Herfindahl   float64 `json:"herfindahl"`
+}
+
+func newJSONConcentration(c app.Concentration) jsonConcentration {
+	return jsonConcentration{
+		Contributors: c.Contributors,
+		Total:        c.Total,
+		BusFactor:    c.BusFactor,
+		Gini:         roundIndex(c.Gini),
+		Herfindahl:   roundIndex(c.Herfindahl),
+	}
+}
+
+// roundIndex rounds f to three decimal places
+func roundIndex(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
+
+func printAnalysisJSON(out io.Writer, a app.Analysis) error {
+	res := jsonAnalysis{
+		SchemaVersion: jsonSchemaVersion,
+		To:            a.To.UTC(),
+		GeneratedAt:   a.GeneratedAt.UTC(),
+		Metric:        a.Metric,
+		Coverage:      a.Coverage,
+		Interval:      a.Interval,
+		Repos:         make([]jsonRepoAnalysis, 0, len(a.Repos)),
+	}
+	if !a.From.IsZero() {
+		from := a.From.UTC()
+		res.From = &from
+	}
+	if a.Interval != "" {
+		res.WindowWeeks = a.Window
+	}
+
+	for _, ra := range a.Repos {
+		jra := jsonRepoAnalysis{Repo: ra.Repo, jsonConcentration: newJSONConcentration(ra.Concentration)}
+		for _, wc := range ra.Trend {
+			jra.Trend = append(jra.Trend, jsonWindowConcentration{
+				From:              wc.From.UTC(),
+				To:                wc.To.UTC(),
+				jsonConcentration: newJSONConcentration(wc.Concentration),
+			})
+		}
+		res.Repos = append(res.Repos, jra)
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+// printAnalysisCSV writes a header row followed by a row per repo, using comma to separate the fields.
+// The rows of each repo's trend follow those of the repos, with window in the kind column instead of range.
+func printAnalysisCSV(out io.Writer, a app.Analysis, comma rune) error {
+	w := csv.NewWriter(out)
+	w.Comma = comma
+
+	w.Write([]string{"repo", "kind", "from", "to", "contributors", a.Metric, "bus_factor", "gini", "herfindahl"})
+	row := func(repo, kind string, from, to time.Time, c app.Concentration) {
+		var fromField string
+		if !from.IsZero() {
+			fromField = from.Format("2006-01-02")
+		}
+		w.Write([]string{
+			repo, kind, fromField, to.Format("2006-01-02"),
+			strconv.Itoa(c.Contributors), strconv.Itoa(c.Total), strconv.Itoa(c.BusFactor),
+			strconv.FormatFloat(roundIndex(c.Gini), 'f', -1, 64), strconv.FormatFloat(roundIndex(c.Herfindahl), 'f', -1, 64),
+		})
+	}
+	for _, ra := range a.Repos {
+		row(ra.Repo, "range", a.From, a.To, ra.Concentration)
+	}
+	for _, ra := range a.Repos {
+		for _, wc := range ra.Trend {
+			row(ra.Repo, "window", wc.From, wc.To, wc.Concentration)
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// printAnalysisMarkdown writes a GitHub flavoured Markdown table of the repos, with a title line,
+// followed by a table of the trend of each repo
+func printAnalysisMarkdown(out io.Writer, a app.Analysis) {
+	fmt.Fprintf(out, "### Bus factor %s\n\nThe bus factor is %s.\n\n", describeRange(a.From, a.To), describeBusFactor(a))
+
+	header := func(first string) {
+		fmt.Fprintf(out, "| %s | Contributors | %s | Bus factor | Gini | Herfindahl |\n| --- | --: | --: | --: | --: | --: |\n", first, capitalise(a.Metric))
+	}
+	line := func(first string, c app.Concentration) {
+		fmt.Fprintf(out, "| %s | %d | %d | %d | %.2f | %.2f |\n", first, c.Contributors, c.Total, c.BusFactor, c.Gini, c.Herfindahl)
+	}
+
+	header("Repository")
+	for _, ra := range a.Repos {
+		line(escapeMarkdown(ra.Repo), ra.Concentration)
+	}
+
+	for _, ra := range a.Repos {
+		if len(ra.Trend) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\n#### Trend of %s, %s\n\n", escapeMarkdown(ra.Repo), describeTrend(a))
+		header("Window")
+		for _, wc := range ra.Trend {
+			line(wc.From.Format("2006-01-02")+" to "+wc.To.Format("2006-01-02"), wc.Concentration)
+		}
+	}
+}
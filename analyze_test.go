@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+// testAnalysis is the analysis used by the analyze output tests
+var testAnalysis = app.Analysis{
+	From:        time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC),
+	To:          time.Date(2018, 9, 1, 0, 0, 0, 0, time.UTC),
+	GeneratedAt: time.Date(2018, 9, 2, 9, 0, 0, 0, time.UTC),
+	Metric:      app.SortCommits,
+	Coverage:    50,
+	Interval:    app.IntervalMonth,
+	Window:      4,
+	Repos: []app.RepoAnalysis{
+		{
+			Repo:          "test-owner/test-repo",
+			Concentration: app.Concentration{Contributors: 2, Total: 10, BusFactor: 1, Gini: 0.4, Herfindahl: 0.82},
+			Trend: []app.WindowConcentration{
+				{
+					From:          time.Date(2018, 7, 4, 0, 0, 0, 0, time.UTC),
+					To:            time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC),
+					Concentration: app.Concentration{Contributors: 1, Total: 4, BusFactor: 1, Herfindahl: 1},
+				},
+				{
+					From:          time.Date(2018, 8, 4, 0, 0, 0, 0, time.UTC),
+					To:            time.Date(2018, 9, 1, 0, 0, 0, 0, time.UTC),
+					Concentration: app.Concentration{Contributors: 3, Total: 9, BusFactor: 2, Gini: 1.0 / 3, Herfindahl: 1.0 / 3},
+				},
+			},
+		},
+	},
+}
+
+func TestPrintAnalysis(t *testing.T) {
+	ts := []struct {
+		Name   string
+		Format string
+		Expect string
+	}{
+		{
+			Name:   "Text",
+			Format: formatText,
+			Expect: `Bus factor: the fewest contributors with 50% of the commits, from 2018-07-01 to 2018-09-01
+Repo: test-owner/test-repo   Contributors: 2   Commits: 10   Bus factor: 1   Gini: 0.40   Herfindahl: 0.82
+
+Trend of test-owner/test-repo, 4 week windows ending each month:
+Window: from 2018-07-04 to 2018-08-01   Contributors: 1   Commits: 4   Bus factor: 1   Gini: 0.00   Herfindahl: 1.00
+Window: from 2018-08-04 to 2018-09-01   Contributors: 3   Commits: 9   Bus factor: 2   Gini: 0.33   Herfindahl: 0.33
+`,
+		},
+		{
+			Name:   "CSV",
+			Format: formatCSV,
+			Expect: `repo,kind,from,to,contributors,commits,bus_factor,gini,herfindahl
+test-owner/test-repo,range,2018-07-01,2018-09-01,2,10,1,0.4,0.82
+test-owner/test-repo,window,2018-07-04,2018-08-01,1,4,1,0,1
+test-owner/test-repo,window,2018-08-04,2018-09-01,3,9,2,0.333,0.333
+`,
+		},
+		{
+			Name:   "Markdown",
+			Format: formatMarkdown,
+			Expect: `### Bus factor from 2018-07-01 to 2018-09-01
+
+The bus factor is the fewest contributors with 50% of the commits.
+
+| Repository | Contributors | Commits | Bus factor | Gini | Herfindahl |
+| --- | --: | --: | --: | --: | --: |
+| test-owner/test-repo | 2 | 10 | 1 | 0.40 | 0.82 |
+
+#### Trend of test-owner/test-repo, 4 week windows ending each month
+
+| Window | Contributors | Commits | Bus factor | Gini | Herfindahl |
+| --- | --: | --: | --: | --: | --: |
+| 2018-07-04 to 2018-08-01 | 1 | 4 | 1 | 0.00 | 1.00 |
+| 2018-08-04 to 2018-09-01 | 3 | 9 | 2 | 0.33 | 0.33 |
+`,
+		},
+		{
+			Name:   "JSON",
+			Format: formatJSON,
+			Expect: `{
+  "schema_version": 1,
+  "from": "2018-07-01T00:00:00Z",
+  "to": "2018-09-01T00:00:00Z",
+  "generated_at": "2018-09-02T09:00:00Z",
+  "metric": "commits",
+  "coverage": 50,
+  "interval": "month",
+  "window_weeks": 4,
+  "repos": [
+    {
+      "repo": "test-owner/test-repo",
+      "contributors": 2,
+      "total": 10,
+      "bus_factor": 1,
+      "gini": 0.4,
+      "herfindahl": 0.82,
+      "trend": [
+        {
+          "from": "2018-07-04T00:00:00Z",
+          "to": "2018-08-01T00:00:00Z",
+          "contributors": 1,
+          "total": 4,
+          "bus_factor": 1,
+          "gini": 0,
+          "herfindahl": 1
+        },
+        {
+          "from": "2018-08-04T00:00:00Z",
+          "to": "2018-09-01T00:00:00Z",
+          "contributors": 3,
+          "total": 9,
+          "bus_factor": 2,
+          "gini": 0.333,
+          "herfindahl": 0.333
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := printAnalysis(&out, testAnalysis, processedInputs{Format: tc.Format}); err != nil {
+				t.Fatalf("printAnalysis: Unexpected Error: %v", err)
+			}
+			if out.String() != tc.Expect {
+				t.Errorf("printAnalysis:\n\nhave result:\n%s\n\nwant result:\n%s", out.String(), tc.Expect)
+			}
+		})
+	}
+}
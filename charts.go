@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/luke-davies/gh-contrib-stats/pkg/chart"
+)
+
+// defaultChartTop is the number of contributors in each chart of the chart command if --top isn't given
+const defaultChartTop = 10
+
+// svgChart is one of the files written by the chart command
+type svgChart struct {
+	File string
+	Draw func(w io.Writer, r app.Report, top int) error
+}
+
+// svgCharts are the files written by the chart command
+var svgCharts = []svgChart{
+	{File: "commits.svg", Draw: drawCommitsChart},
+	{File: "cumulative.svg", Draw: drawCumulativeChart},
+	{File: "top.svg", Draw: drawTopChart},
+}
+
+// drawCommitsChart draws a line of the commits per week of each of the top contributors by commits
+func drawCommitsChart(w io.Writer, r app.Report, top int) error {
+	weeks := r.Weeks()
+	cs, _ := topContributors(r.Contributors, top, func(s app.Stats) int { return s.Commits })
+
+	series := make([]chart.Series, len(cs))
+	for i, c := range cs {
+		series[i] = chart.Series{Name: c.Name, Values: weeklyCommits(c, weeks)}
+	}
+	return chart.Lines(w, chart.Options{Title: "Commits per week"}, weekLabels(weeks), series)
+}
+
+// drawCumulativeChart draws a line of the running total of lines changed (additions + deletions)
+// of each of the top contributors by lines changed
+func drawCumulativeChart(w io.Writer, r app.Report, top int) error {
+	weeks := r.Weeks()
+	cs, _ := topContributors(r.Contributors, top, func(s app.Stats) int { return s.Additions + s.Deletions })
+
+	series := make([]chart.Series, len(cs))
+	for i, c := range cs {
+		values := make([]float64, len(weeks))
+		total := 0
+		for j, s := range c.WeeklyStats(weeks) {
+			total += s.Additions + s.Deletions
+			values[j] = float64(total)
+		}
+		series[i] = chart.Series{Name: c.Name, Values: values}
+	}
+	return chart.Lines(w, chart.Options{Title: "Cumulative lines changed"}, weekLabels(weeks), series)
+}
+
+// drawTopChart draws a bar of the commits of each of the top contributors by commits
+func drawTopChart(w io.Writer, r app.Report, top int) error {
+	cs, _ := topContributors(r.Contributors, top, func(s app.Stats) int { return s.Commits })
+
+	labels := make([]string, len(cs))
+	commits := chart.Series{Name: "Commits", Values: make([]float64, len(cs))}
+	for i, c := range cs {
+		labels[i] = c.Name
+		commits.Values[i] = float64(c.Stats.Commits)
+	}
+	return chart.Bars(w, chart.Options{Title: fmt.Sprintf("Top %d contributors by commits", len(cs))}, labels, []chart.Series{commits})
+}
+
+func weekLabels(weeks []time.Time) []string {
+	labels := make([]string, len(weeks))
+	for i, wb := range weeks {
+		labels[i] = wb.Format("2006-01-02")
+	}
+	return labels
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDrawCharts(t *testing.T) {
+	ts := []struct {
+		Name   string
+		Draw   svgChart
+		Top    int
+		Expect []string
+		Omit   []string
+	}{
+		{
+			Name: "Commits",
+			Draw: svgCharts[0],
+			Top:  1,
+			// only test-user-1 fits in the top 1
+			Expect: []string{"Commits per week", "<polyline", "<title>test-user-1</title>", "2018-06-03"},
+			Omit:   []string{"Knope"},
+		},
+		{
+			Name: "Cumulative",
+			Draw: svgCharts[1],
+			Top:  10,
+			// test-user-1 changed 65 lines in the first week and 150 by the end
+			Expect: []string{"Cumulative lines changed", "<title>test-user-1</title>", "<title>Knope, Leslie</title>", ">150</text>"},
+		},
+		{
+			Name:   "Top",
+			Draw:   svgCharts[2],
+			Top:    10,
+			Expect: []string{"Top 2 contributors by commits", "test-user-1 Commits: 3", "Knope, Leslie Commits: 1"},
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tc.Draw.Draw(&buf, testReport, tc.Top); err != nil {
+				t.Fatalf("%s: Unexpected Error: %v", tc.Draw.File, err)
+			}
+			for _, want := range tc.Expect {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("%s: output doesn't contain %q:\n%s", tc.Draw.File, want, buf.String())
+				}
+			}
+			for _, omit := range tc.Omit {
+				if strings.Contains(buf.String(), omit) {
+					t.Errorf("%s: output shouldn't contain %q:\n%s", tc.Draw.File, omit, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestWriteChart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gh-contrib-stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "top.svg")
+	if err := writeChart(file, svgCharts[2], testReport, 10); err != nil {
+		t.Fatalf("writeChart: Unexpected Error: %v", err)
+	}
+	b, err := ioutil.ReadFile(file)
+	if err != nil || !strings.HasPrefix(string(b), "<svg") {
+		t.Errorf("writeChart: expected an SVG file, have %v:\n%s", err, b)
+	}
+
+	if err := writeChart(filepath.Join(dir, "missing", "top.svg"), svgCharts[2], testReport, 10); err == nil {
+		t.Error("writeChart: Expected error but received nil")
+	}
+}
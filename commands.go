@@ -0,0 +1,639 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+	"github.com/pkg/errors"
+)
+
+// calcOpts returns the normalised and validated options for the date range
+func calcOpts(inputs processedInputs) (app.CalcContrbutionsOpts, error) {
+	opts := app.NormaliseCalcContributionsOpts(app.CalcContrbutionsOpts{From: inputs.From, To: inputs.To})
+	return opts, app.ValidateCalcContributionsOpts(opts)
+}
+
+// configs are the files given by inputs that shape a report, see loadConfigs
+type configs struct {
+	Template   *template.Template
+	Exclusions app.Exclusions
+	Aliases    app.Aliases
+	Teams      []app.TeamMembers
+}
+
+// loadConfigs loads the template, login lists, aliases and teams given by inputs.
+// The commands load them before fetching any stats so that mistakes show up before waiting on GitHub.
+func loadConfigs(ctx context.Context, client github.Client, inputs processedInputs) (configs, error) {
+	var cfg configs
+	var err error
+	if cfg.Template, err = loadTemplate(inputs); err != nil {
+		return configs{}, err
+	}
+	if cfg.Exclusions, err = loadExclusions(inputs); err != nil {
+		return configs{}, err
+	}
+	if cfg.Aliases, err = loadAliases(inputs); err != nil {
+		return configs{}, err
+	}
+	if inputs.GroupBy == app.GroupByTeam {
+		if cfg.Teams, err = loadTeams(ctx, client, inputs, cfg.Aliases); err != nil {
+			return configs{}, err
+		}
+	}
+	return cfg, nil
+}
+
+func runContributors(ctx context.Context, client github.Client, inputs processedInputs) error {
+	opts, err := calcOpts(inputs)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfigs(ctx, client, inputs)
+	if err != nil {
+		return err
+	}
+
+	report, fetchErr := contributorsReport(ctx, client, inputs, cfg, opts)
+	if fetchErr != nil && !partialFailure(fetchErr) {
+		return fetchErr
+	}
+
+	report.Contributors = app.SortContributors(report.Contributors, inputs.Sort)
+	for i, c := range report.Contributors {
+		if len(c.Members) > 0 {
+			report.Contributors[i].Members = app.SortContributors(c.Members, inputs.Sort)
+		}
+	}
+	if inputs.Top > 0 && len(report.Contributors) > inputs.Top {
+		report.Contributors = report.Contributors[:inputs.Top]
+	}
+
+	if cfg.Template != nil {
+		err = cfg.Template.Execute(os.Stdout, report)
+	} else {
+		err = printReport(os.Stdout, report, inputs)
+	}
+	if err != nil {
+		return err
+	}
+	return fetchErr
+}
+
+// runCompare compares the contributions in the date range given by inputs against those in the previous range,
+// either --compare-from and --compare-to or app.PreviousRange.
+func runCompare(ctx context.Context, client github.Client, inputs processedInputs) error {
+	current, err := calcOpts(inputs)
+	if err != nil {
+		return err
+	}
+	previous := app.PreviousRange(current)
+	if !inputs.CompareFrom.IsZero() {
+		previous = app.CalcContrbutionsOpts{From: inputs.CompareFrom, To: inputs.CompareTo}
+	}
+	if err := app.ValidateCalcContributionsOpts(previous); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfigs(ctx, client, inputs)
+	if err != nil {
+		return err
+	}
+
+	reports, fetchErr := contributorsReports(ctx, client, inputs, cfg, previous, current)
+	if fetchErr != nil && !partialFailure(fetchErr) {
+		return fetchErr
+	}
+
+	for i := range reports {
+		reports[i].Contributors = app.SortContributors(reports[i].Contributors, inputs.Sort)
+	}
+	cmp := app.CompareReports(reports[0], reports[1])
+	if inputs.Top > 0 && len(cmp.Changes) > inputs.Top {
+		cmp.Changes = cmp.Changes[:inputs.Top]
+	}
+
+	if cfg.Template != nil {
+		err = cfg.Template.Execute(os.Stdout, cmp)
+	} else {
+		err = printComparison(os.Stdout, cmp, inputs)
+	}
+	if err != nil {
+		return err
+	}
+	return fetchErr
+}
+
+// runAnalyze analyses how concentrated the contributions to each repo are in the date range given by inputs and,
+// with --interval, in each of the rolling windows of the trend. See app.Analyze.
+func runAnalyze(ctx context.Context, client github.Client, inputs processedInputs) error {
+	opts, err := calcOpts(inputs)
+	if err != nil {
+		return err
+	}
+	var windows []app.CalcContrbutionsOpts
+	if inputs.Interval != "" {
+		windows = app.RollingWindows(opts, inputs.Interval, inputs.Window)
+	}
+
+	cfg, err := loadConfigs(ctx, client, inputs)
+	if err != nil {
+		return err
+	}
+
+	reports, fetchErr := contributorsReports(ctx, client, inputs, cfg, append([]app.CalcContrbutionsOpts{opts}, windows...)...)
+	if fetchErr != nil && !partialFailure(fetchErr) {
+		return fetchErr
+	}
+
+	analysis := app.Analyze(reports[0], reports[1:], inputs.Metric, inputs.Coverage)
+	analysis.Window = inputs.Window
+
+	if cfg.Template != nil {
+		err = cfg.Template.Execute(os.Stdout, analysis)
+	} else {
+		err = printAnalysis(os.Stdout, analysis, inputs)
+	}
+	if err != nil {
+		return err
+	}
+	return fetchErr
+}
+
+// contributorsReport fetches and calculates the contributions to the repos given by inputs, shaped by cfg.
+// If only some of the repos failed, the report covers the others and the error lists the failures. See partialFailure.
+func contributorsReport(ctx context.Context, client github.Client, inputs processedInputs, cfg configs, opts app.CalcContrbutionsOpts) (app.Report, error) {
+	reports, err := contributorsReports(ctx, client, inputs, cfg, opts)
+	if reports == nil {
+		return app.Report{}, err
+	}
+	return reports[0], err
+}
+
+// contributorsReports is like contributorsReport but returns a report for each of the date ranges.
+// The stats of each repo are only fetched once, for all of the ranges.
+func contributorsReports(ctx context.Context, client github.Client, inputs processedInputs, cfg configs, ranges ...app.CalcContrbutionsOpts) ([]app.Report, error) {
+	repos, err := resolveRepos(ctx, client, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	// rcs[range][repo], left zero for repos that failed
+	rcs := make([][]app.RepoContributors, len(ranges))
+	for i := range rcs {
+		rcs[i] = make([]app.RepoContributors, len(repos))
+	}
+	fetchErr := forEachRepo(ctx, client, inputs, repos, func(ctx context.Context, c github.Client, i int, r repoArg) error {
+		acs, err := repoContributors(ctx, c, r, inputs, ranges)
+		if err != nil {
+			return err
+		}
+		for j := range ranges {
+			rcs[j][i] = app.RepoContributors{Repo: r.String(), Contributors: acs[j]}
+		}
+		return nil
+	})
+	if fetchErr != nil && !partialFailure(fetchErr) {
+		return nil, fetchErr
+	}
+
+	reports := make([]app.Report, len(ranges))
+	for i, opts := range ranges {
+		report := app.Report{From: opts.From, To: opts.To, GeneratedAt: time.Now()}
+		for _, rc := range rcs[i] {
+			if rc.Repo != "" {
+				report.Repos = append(report.Repos, rc.Repo)
+			}
+		}
+
+		acs := app.MergeContributors(rcs[i])
+		if cfg.Aliases != nil {
+			acs = app.MergeAliases(acs, cfg.Aliases)
+		}
+		acs = app.WithRepoTotal(acs)
+
+		if !inputs.All {
+			acs = app.FilterContributors(acs, func(ac app.Contributor) bool { return ac.Stats.Commits > 0 })
+		}
+		acs, report.Excluded = app.ExcludeContributors(acs, cfg.Exclusions)
+		if report.Excluded.Contributors > 0 {
+			if len(ranges) > 1 {
+				fmt.Fprintf(os.Stderr, "%s: ", describeRange(opts.From, opts.To))
+			}
+			fmt.Fprintln(os.Stderr, describeExcluded(report.Excluded))
+		}
+
+		report.Contributors = acs
+		reports[i] = report
+	}
+
+	if inputs.Where != nil {
+		where := inputs.Where
+		if inputs.Command == cmdCompare {
+			// otherwise someone matching in only one of the ranges would look like they
+			// had no commits in the other, see whereAnyRange
+			where = whereAnyRange(reports, inputs.Where)
+		}
+		for i := range reports {
+			reports[i].Contributors = app.FilterContributors(reports[i].Contributors, where)
+		}
+	}
+
+	for i := range reports {
+		reports[i].GroupBy = inputs.GroupBy
+		reports[i].Interval = inputs.Interval
+		if inputs.GroupBy == app.GroupByTeam {
+			reports[i].Contributors = app.GroupByTeams(reports[i].Contributors, cfg.Teams)
+		}
+	}
+	return reports, fetchErr
+}
+
+// whereAnyRange returns a filter keeping the contributors that match where in any of the reports,
+// so that they're kept in all of them
+func whereAnyRange(reports []app.Report, where func(app.Contributor) bool) func(app.Contributor) bool {
+	matched := make(map[string]bool)
+	for _, r := range reports {
+		for _, c := range r.Contributors {
+			if where(c) {
+				matched[c.Name] = true
+			}
+		}
+	}
+	return func(c app.Contributor) bool { return matched[c.Name] }
+}
+
+func runChart(ctx context.Context, client github.Client, inputs processedInputs) error {
+	opts, err := calcOpts(inputs)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfigs(ctx, client, inputs)
+	if err != nil {
+		return err
+	}
+
+	report, fetchErr := contributorsReport(ctx, client, inputs, cfg, opts)
+	if fetchErr != nil && !partialFailure(fetchErr) {
+		return fetchErr
+	}
+
+	top := inputs.Top
+	if top == 0 {
+		top = defaultChartTop
+	}
+
+	for _, c := range svgCharts {
+		file := filepath.Join(inputs.OutDir, c.File)
+		if err := writeChart(file, c, report, top); err != nil {
+			return err
+		}
+		fmt.Println(file)
+	}
+	return fetchErr
+}
+
+func writeChart(file string, c svgChart, r app.Report, top int) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return errors.Wrap(err, "[writeChart] could not create chart")
+	}
+	err = c.Draw(f, r, top)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return errors.Wrapf(err, "[writeChart] could not write %s", file)
+}
+
+// repoContributors calculates the contributions to a single repo with the precision given by inputs, for each of the date ranges
+func repoContributors(ctx context.Context, client github.Client, r repoArg, inputs processedInputs, ranges []app.CalcContrbutionsOpts) ([][]app.Contributor, error) {
+	res := make([][]app.Contributor, len(ranges))
+
+	if inputs.Precision == precisionDay {
+		commits, err := listCommitsWithStats(ctx, client, r.Owner, r.Name, spanRanges(ranges), inputs.Parallel)
+		if err != nil {
+			return nil, err
+		}
+		for i, opts := range ranges {
+			res[i] = app.CalcCommitContributions(commits, opts)
+		}
+		return res, nil
+	}
+
+	gcs, err := client.ListContributorStats(ctx, r.Owner, r.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, opts := range ranges {
+		for _, gc := range *gcs {
+			res[i] = append(res[i], app.CalcContributions(gc, opts))
+		}
+	}
+	return res, nil
+}
+
+// spanRanges returns the date range that covers all of the given ranges
+func spanRanges(ranges []app.CalcContrbutionsOpts) app.CalcContrbutionsOpts {
+	res := ranges[0]
+	for _, opts := range ranges[1:] {
+		if opts.From.Before(res.From) {
+			res.From = opts.From
+		}
+		if opts.To.After(res.To) {
+			res.To = opts.To
+		}
+	}
+	return res
+}
+
+// resolveRepos expands --org and any patterns given as arguments into the repositories to query.
+// Forks and archived repositories are left out of those unless --include-forks or --include-archived is set.
+// Repositories are returned in the order they were given, without duplicates.
+func resolveRepos(ctx context.Context, client github.Client, inputs processedInputs) ([]repoArg, error) {
+	var res []repoArg
+	seen := make(map[string]bool)
+	add := func(r repoArg) {
+		if !seen[r.String()] {
+			seen[r.String()] = true
+			res = append(res, r)
+		}
+	}
+
+	// cached so that several patterns for the same owner only list its repos once.
+	// An owner that isn't an organisation (404) is listed as a user instead.
+	ownerRepos := make(map[string][]github.Repository)
+	listOwner := func(owner string) ([]github.Repository, error) {
+		if repos, ok := ownerRepos[owner]; ok {
+			return repos, nil
+		}
+		repos, err := client.ListOrgRepos(ctx, owner)
+		if apiErr, ok := errors.Cause(err).(*github.APIError); ok && apiErr.StatusCode == http.StatusNotFound {
+			repos, err = client.ListUserRepos(ctx, owner)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ownerRepos[owner] = *repos
+		return *repos, nil
+	}
+
+	// repositories named as arguments are always included
+	skip := func(gr github.Repository) bool {
+		return (gr.Fork && !inputs.IncludeForks) || (gr.Archived && !inputs.IncludeArchived)
+	}
+
+	if inputs.Org != "" {
+		repos, err := listOwner(inputs.Org)
+		if err != nil {
+			return nil, err
+		}
+		for _, gr := range repos {
+			if !skip(gr) {
+				add(repoArg{Owner: gr.Owner.Login, Name: gr.Name})
+			}
+		}
+	}
+
+	for _, r := range inputs.Repos {
+		if !r.IsPattern() {
+			add(r)
+			continue
+		}
+		repos, err := listOwner(r.Owner)
+		if err != nil {
+			return nil, err
+		}
+		for _, gr := range repos {
+			if ok, _ := path.Match(r.Name, gr.Name); ok && !skip(gr) {
+				add(repoArg{Owner: r.Owner, Name: gr.Name})
+			}
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("[resolveRepos] no repositories found")
+	}
+	return res, nil
+}
+
+// forEachRepo calls f for each repo, up to inputs.Parallel at once, with a client whose progress messages name the repo.
+// If only some of the repos fail the returned *github.BatchError lists them, in which case
+// the caller should output the results for the other repos before returning it. See partialFailure.
+func forEachRepo(ctx context.Context, client github.Client, inputs processedInputs, repos []repoArg, f func(ctx context.Context, c github.Client, i int, r repoArg) error) error {
+	names := make([]string, len(repos))
+	for i, r := range repos {
+		names[i] = r.String()
+	}
+	return client.ForEachRepo(ctx, names, inputs.Parallel, func(ctx context.Context, i int) error {
+		return f(ctx, forRepo(client, repos[i]), i, repos[i])
+	})
+}
+
+// partialFailure returns true if err is a *github.BatchError where at least one repo succeeded
+func partialFailure(err error) bool {
+	batchErr, ok := err.(*github.BatchError)
+	return ok && len(batchErr.Errors) < batchErr.Total
+}
+
+// forRepo returns a copy of the client whose progress messages name the repo
+func forRepo(client github.Client, r repoArg) github.Client {
+	client.Poll.OnPending = func(attempt int, wait time.Duration) {
+		fmt.Fprintf(os.Stderr, "waiting for GitHub to compute stats for %s (attempt %d, retrying in %s)\n", r, attempt, wait)
+	}
+	return client
+}
+
+// listCommitsWithStats lists the commits in the date range and then gets each one for its stats, up to parallel at once.
+// Merge commits are left out as their changes are already counted in the commits they merge.
+func listCommitsWithStats(ctx context.Context, client github.Client, owner, repo string, opts app.CalcContrbutionsOpts, parallel int) ([]github.Commit, error) {
+	commits, err := client.ListCommits(ctx, owner, repo, opts.From, opts.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var shas []string
+	for _, c := range *commits {
+		if len(c.Parents) <= 1 {
+			shas = append(shas, c.SHA)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "fetching stats for %d commits in %s/%s\n", len(shas), owner, repo)
+
+	res := make([]github.Commit, len(shas))
+	err = client.ForEachRepo(ctx, shas, parallel, func(ctx context.Context, i int) error {
+		commit, err := client.GetCommit(ctx, owner, repo, shas[i])
+		if err != nil {
+			return err
+		}
+		res[i] = *commit
+		return nil
+	})
+	// without every commit the stats would be wrong, so any failure fails the repo
+	if batchErr, ok := err.(*github.BatchError); ok {
+		re := batchErr.Errors[0]
+		return nil, errors.Wrapf(re.Err, "[listCommitsWithStats] could not get %d of %d commits in %s/%s, the first was %s",
+			len(batchErr.Errors), len(shas), owner, repo, re.Repo)
+	}
+	return res, err
+}
+
+func runActivity(ctx context.Context, client github.Client, inputs processedInputs) error {
+	opts, err := calcOpts(inputs)
+	if err != nil {
+		return err
+	}
+
+	repos, err := resolveRepos(ctx, client, inputs)
+	if err != nil {
+		return err
+	}
+
+	activities := make([][]github.CommitActivity, len(repos))
+	frequencies := make([][]github.CodeFrequency, len(repos))
+	fetchErr := forEachRepo(ctx, client, inputs, repos, func(ctx context.Context, c github.Client, i int, r repoArg) error {
+		a, err := c.ListCommitActivity(ctx, r.Owner, r.Name)
+		if err != nil {
+			return err
+		}
+
+		f, err := c.ListCodeFrequency(ctx, r.Owner, r.Name)
+		if err != nil {
+			return err
+		}
+
+		activities[i], frequencies[i] = *a, *f
+		return nil
+	})
+	if fetchErr != nil && !partialFailure(fetchErr) {
+		return fetchErr
+	}
+
+	var activity []github.CommitActivity
+	var frequency []github.CodeFrequency
+	for i := range repos {
+		activity = append(activity, activities[i]...)
+		frequency = append(frequency, frequencies[i]...)
+	}
+
+	printActivity(os.Stdout, app.CalcWeeklyActivity(activity, frequency, opts))
+	return fetchErr
+}
+
+func runParticipation(ctx context.Context, client github.Client, inputs processedInputs) error {
+	opts, err := calcOpts(inputs)
+	if err != nil {
+		return err
+	}
+
+	repos, err := resolveRepos(ctx, client, inputs)
+	if err != nil {
+		return err
+	}
+
+	results := make([]*github.Participation, len(repos))
+	fetchErr := forEachRepo(ctx, client, inputs, repos, func(ctx context.Context, c github.Client, i int, r repoArg) error {
+		p, err := c.GetParticipation(ctx, r.Owner, r.Name)
+		results[i] = p
+		return err
+	})
+	if fetchErr != nil && !partialFailure(fetchErr) {
+		return fetchErr
+	}
+
+	var participations []github.Participation
+	for _, p := range results {
+		if p != nil {
+			participations = append(participations, *p)
+		}
+	}
+
+	printParticipation(os.Stdout, app.CalcParticipation(app.MergeParticipation(participations), opts, time.Now()))
+	return fetchErr
+}
+
+func runPunchCard(ctx context.Context, client github.Client, inputs processedInputs) error {
+	repos, err := resolveRepos(ctx, client, inputs)
+	if err != nil {
+		return err
+	}
+
+	results := make([][]github.PunchCard, len(repos))
+	fetchErr := forEachRepo(ctx, client, inputs, repos, func(ctx context.Context, c github.Client, i int, r repoArg) error {
+		pc, err := c.ListPunchCard(ctx, r.Owner, r.Name)
+		if err != nil {
+			return err
+		}
+		results[i] = *pc
+		return nil
+	})
+	if fetchErr != nil && !partialFailure(fetchErr) {
+		return fetchErr
+	}
+
+	var punchCard []github.PunchCard
+	for _, pc := range results {
+		punchCard = append(punchCard, pc...)
+	}
+
+	printPunchCard(os.Stdout, app.CalcPunchCard(punchCard))
+	return fetchErr
+}
+
+func printActivity(out io.Writer, weeks []app.WeekActivity) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, week := range weeks {
+		fmt.Fprint(w, week.String())
+	}
+	w.Flush()
+}
+
+func printParticipation(out io.Writer, p app.Participation) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, week := range p.Weeks {
+		fmt.Fprint(w, week.String())
+	}
+	fmt.Fprintf(w, "Total\t Owner: %d\t Non-owner: %d\t Owner share: %.1f%%\t\n", p.Owner, p.NonOwner, p.OwnerShare())
+	w.Flush()
+}
+
+// heatmap shades, from no commits to the busiest hour
+var heatmapShades = []string{"  ", "░░", "▒▒", "▓▓", "██"}
+
+func printPunchCard(out io.Writer, pc app.PunchCard) {
+	days := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	max := pc.Max()
+
+	fmt.Fprint(out, "   ")
+	for h := 0; h < 24; h++ {
+		fmt.Fprintf(out, " %02d", h)
+	}
+	fmt.Fprintln(out)
+
+	for d, hours := range pc {
+		fmt.Fprint(out, days[d])
+		for _, c := range hours {
+			shade := 0
+			if c > 0 {
+				// any commits get at least the lightest shade
+				shade = 1 + (c*(len(heatmapShades)-2))/max
+			}
+			fmt.Fprint(out, " "+heatmapShades[shade])
+		}
+		fmt.Fprintln(out)
+	}
+
+	fmt.Fprintf(out, "\nBusiest hour: %d commits. Shades: %s\n", max, strings.Join(heatmapShades[1:], " "))
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+)
+
+func TestPrintParticipation(t *testing.T) {
+	var buf bytes.Buffer
+	printParticipation(&buf, app.Participation{
+		Weeks: []app.ParticipationWeek{
+			{WeekBeginning: time.Date(2018, 6, 24, 0, 0, 0, 0, time.UTC), Owner: 2, NonOwner: 8},
+		},
+		Owner:    2,
+		NonOwner: 8,
+	})
+
+	for _, want := range []string{"Week beginning: 2018-06-24", "Owner share: 20.0%"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("printParticipation: output doesn't contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestPrintPunchCard(t *testing.T) {
+	var pc app.PunchCard
+	pc[1][9] = 1
+	pc[3][14] = 40
+
+	var buf bytes.Buffer
+	printPunchCard(&buf, pc)
+	lines := strings.Split(buf.String(), "\n")
+
+	// header + 7 days
+	if !strings.HasPrefix(lines[2], "Mon") || !strings.HasPrefix(lines[4], "Wed") {
+		t.Fatalf("printPunchCard: unexpected layout:\n%s", buf.String())
+	}
+
+	// each row is the day followed by a space and a 2 character shade per hour
+	cell := func(line string, hour int) string {
+		return string([]rune(line)[4+3*hour : 6+3*hour])
+	}
+	if have := cell(lines[2], 9); have != heatmapShades[1] {
+		t.Errorf("printPunchCard: have %q for a quiet hour want %q", have, heatmapShades[1])
+	}
+	if have := cell(lines[4], 14); have != heatmapShades[len(heatmapShades)-1] {
+		t.Errorf("printPunchCard: have %q for the busiest hour want %q", have, heatmapShades[len(heatmapShades)-1])
+	}
+}
+
+func TestResolveRepos(t *testing.T) {
+	orgRequests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgRequests++
+		// test-user is a user, not an organisation
+		if r.URL.Path == "/orgs/test-user/repos" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"name":"api-users","owner":{"login":"test-owner"}},
+			{"name":"api-orders","owner":{"login":"test-owner"}},
+			{"name":"website","owner":{"login":"test-owner"}},
+			{"name":"api-fork","owner":{"login":"test-owner"},"fork":true},
+			{"name":"api-old","owner":{"login":"test-owner"},"archived":true}
+		]`)
+	}))
+	defer mockServer.Close()
+	client := github.Client{BaseURL: mockServer.URL}
+
+	ts := []struct {
+		Name              string
+		Inputs            processedInputs
+		ExpectRes         []repoArg
+		ExpectOrgRequests int
+	}{
+		{
+			Name: "Plain",
+			Inputs: processedInputs{Repos: []repoArg{
+				{Owner: "test-owner", Name: "website"},
+				{Owner: "other-owner", Name: "other-repo"},
+			}},
+			ExpectRes: []repoArg{
+				{Owner: "test-owner", Name: "website"},
+				{Owner: "other-owner", Name: "other-repo"},
+			},
+		},
+		{
+			Name: "Patterns",
+			Inputs: processedInputs{Repos: []repoArg{
+				{Owner: "test-owner", Name: "api-*"},
+				{Owner: "test-owner", Name: "api-users"},
+				{Owner: "test-owner", Name: "web*"},
+			}},
+			ExpectRes: []repoArg{
+				{Owner: "test-owner", Name: "api-users"},
+				{Owner: "test-owner", Name: "api-orders"},
+				{Owner: "test-owner", Name: "website"},
+			},
+			ExpectOrgRequests: 1,
+		},
+		{
+			Name:   "User Pattern",
+			Inputs: processedInputs{Repos: []repoArg{{Owner: "test-user", Name: "web*"}}},
+			ExpectRes: []repoArg{
+				{Owner: "test-user", Name: "website"},
+			},
+			ExpectOrgRequests: 2,
+		},
+		{
+			Name:   "Org",
+			Inputs: processedInputs{Org: "test-owner"},
+			ExpectRes: []repoArg{
+				{Owner: "test-owner", Name: "api-users"},
+				{Owner: "test-owner", Name: "api-orders"},
+				{Owner: "test-owner", Name: "website"},
+			},
+			ExpectOrgRequests: 1,
+		},
+		{
+			Name: "Forks And Archived",
+			Inputs: processedInputs{
+				Repos:        []repoArg{{Owner: "test-owner", Name: "api-*"}, {Owner: "test-owner", Name: "api-old"}},
+				IncludeForks: true,
+			},
+			ExpectRes: []repoArg{
+				{Owner: "test-owner", Name: "api-users"},
+				{Owner: "test-owner", Name: "api-orders"},
+				{Owner: "test-owner", Name: "api-fork"},
+				{Owner: "test-owner", Name: "api-old"},
+			},
+			ExpectOrgRequests: 1,
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			orgRequests = 0
+			res, err := resolveRepos(context.Background(), client, tc.Inputs)
+			if err != nil {
+				t.Fatalf("resolveRepos: Unexpected Error: %v", err)
+			}
+			if !reflect.DeepEqual(res, tc.ExpectRes) {
+				t.Errorf("resolveRepos:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, tc.ExpectRes)
+			}
+			if orgRequests != tc.ExpectOrgRequests {
+				t.Errorf("resolveRepos: have %d org requests want %d", orgRequests, tc.ExpectOrgRequests)
+			}
+		})
+	}
+
+	_, err := resolveRepos(context.Background(), client, processedInputs{Repos: []repoArg{{Owner: "test-owner", Name: "nope-*"}}})
+	if err == nil {
+		t.Error("resolveRepos: Expected error but received nil")
+	}
+}
+
+func TestContributorsReportsCompareWhere(t *testing.T) {
+	// test-user-1 has 10 commits in the week of 2018-04-01 and 3 in the week of 2018-07-01
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"author":{"login":"test-user-1"},"weeks":[{"w":1522540800,"a":100,"d":0,"c":10},{"w":1530403200,"a":30,"d":0,"c":3}]},
+			{"author":{"login":"test-user-2"},"weeks":[{"w":1530403200,"a":60,"d":0,"c":6}]},
+			{"author":{"login":"test-user-3"},"weeks":[{"w":1522540800,"a":20,"d":0,"c":2},{"w":1530403200,"a":10,"d":0,"c":1}]}
+		]`)
+	}))
+	defer mockServer.Close()
+	client := github.Client{BaseURL: mockServer.URL}
+
+	where, err := app.CompileWhere("commits >= 5")
+	if err != nil {
+		t.Fatalf("CompileWhere: Unexpected Error: %v", err)
+	}
+	inputs := processedInputs{
+		Command:   cmdCompare,
+		Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+		Precision: precisionWeek,
+		Where:     where,
+	}
+	previous := app.CalcContrbutionsOpts{From: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)}
+	current := app.CalcContrbutionsOpts{From: time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)}
+
+	reports, err := contributorsReports(context.Background(), client, inputs, configs{}, previous, current)
+	if err != nil {
+		t.Fatalf("contributorsReports: Unexpected Error: %v", err)
+	}
+
+	// test-user-1 only matches in the previous range but is kept in both, test-user-3 never matches
+	var have []string
+	for _, c := range app.CompareReports(reports[0], reports[1]).Changes {
+		have = append(have, fmt.Sprintf("%s %d→%d %s", c.Name, c.Previous.Commits, c.Current.Commits, c.Status))
+	}
+	want := []string{"test-user-1 10→3 active", "test-user-2 0→6 new"}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("contributorsReports:\n\nhave changes:\n%v\n\nwant changes:\n%v", have, want)
+	}
+}
+
+func TestListCommitsWithStats(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight, gets := 0, 0, 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/test-owner/test-repo/commits" {
+			fmt.Fprint(w, `[
+				{"sha":"a","parents":[{"sha":"z"}]},
+				{"sha":"merge","parents":[{"sha":"a"},{"sha":"b"}]},
+				{"sha":"b","parents":[{"sha":"z"}]},
+				{"sha":"c","parents":[]}
+			]`)
+			return
+		}
+
+		mu.Lock()
+		gets++
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		sha := strings.TrimPrefix(r.URL.Path, "/repos/test-owner/test-repo/commits/")
+		fmt.Fprintf(w, `{"sha":%q,"stats":{"additions":1}}`, sha)
+	}))
+	defer mockServer.Close()
+	client := github.Client{BaseURL: mockServer.URL}
+
+	res, err := listCommitsWithStats(context.Background(), client, "test-owner", "test-repo", app.CalcContrbutionsOpts{}, 2)
+	if err != nil {
+		t.Fatalf("listCommitsWithStats: Unexpected Error: %v", err)
+	}
+
+	var shas []string
+	for _, c := range res {
+		shas = append(shas, c.SHA)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(shas, want) {
+		t.Errorf("listCommitsWithStats: have commits %v want %v", shas, want)
+	}
+	if gets != 3 || maxInFlight > 2 {
+		t.Errorf("listCommitsWithStats: have %d requests, %d at once want 3, at most 2 at once", gets, maxInFlight)
+	}
+}
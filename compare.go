@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+// statusLabels describes the statuses of a change in the text and markdown output.
+// Contributors that were active in both ranges aren't labelled.
+var statusLabels = map[string]string{
+	app.StatusNew:      "newly active",
+	app.StatusInactive: "no longer active",
+}
+
+// printComparison writes the comparison in the format given by inputs
+func printComparison(out io.Writer, cmp app.Comparison, inputs processedInputs) error {
+	switch inputs.Format {
+	case formatJSON:
+		return printComparisonJSON(out, cmp)
+	case formatCSV:
+		return printComparisonCSV(out, cmp, ',')
+	case formatTSV:
+		return printComparisonCSV(out, cmp, '\t')
+	case formatMarkdown:
+		printComparisonMarkdown(out, cmp)
+		return nil
+	default:
+		printChanges(out, cmp)
+		return nil
+	}
+}
+
+// printChanges writes both date ranges, followed by a line per contributor with each of their stats in the
+// previous and current range and the change between them
+func printChanges(out io.Writer, cmp app.Comparison) {
+	fmt.Fprintf(out, "Previous: %s\n", describeRange(cmp.Previous.From, cmp.Previous.To))
+	fmt.Fprintf(out, "Current: %s\n", describeRange(cmp.Current.From, cmp.Current.To))
+
+	kind := "Contributor"
+	if cmp.Current.GroupBy == app.GroupByTeam {
+		kind = "Team"
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, c := range cmp.Changes {
+		fmt.Fprintf(w, "%s: %s\t Commits: %s\t Additions: %s\t Deletions: %s\t %s\n",
+			kind, c.Name,
+			formatChange(c.Previous.Commits, c.Current.Commits),
+			formatChange(c.Previous.Additions, c.Current.Additions),
+			formatChange(c.Previous.Deletions, c.Current.Deletions),
+			statusLabels[c.Status],
+		)
+	}
+	w.Flush()
+}
+
+// formatChange formats the change from previous to current e.g. "10 → 15 (+5, +50.0%)".
+// The percentage is left out when previous is zero, see app.PercentChange.
+func formatChange(previous, current int) string {
+	if pc, ok := app.PercentChange(previous, current); ok {
+		return fmt.Sprintf("%d → %d (%+d, %+.1f%%)", previous, current, current-previous, pc)
+	}
+	return fmt.Sprintf("%d → %d (%+d)", previous, current, current-previous)
+}
+
+// formatPercentChange formats the percentage change from previous to current with one decimal place e.g. "-12.5".
+// It's empty when previous is zero, see app.PercentChange.
+func formatPercentChange(previous, current int) string {
+	if pc, ok := app.PercentChange(previous, current); ok {
+		return strconv.FormatFloat(pc, 'f', 1, 64)
+	}
+	return ""
+}
+
+// jsonComparison is the JSON output of the compare command, see jsonReport
+type jsonComparison struct {
+	SchemaVersion int          `json:"schema_version"`
+	Repos         []string     `json:"repos"`
+	Previous      jsonRange    `json:"previous"`
+	Current       jsonRange    `json:"current"`
+	GeneratedAt   time.Time    `json:"generated_at"`
+	GroupBy       string       `json:"group_by"`
+	Contributors  []jsonChange `json:"contributors"`
+}
+
+type jsonRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// jsonChange is the change of a contributor in the JSON output.
+// Status is one of new, inactive, active and idle (see app.Change).
+// PercentChange is null for the stats that were zero in the previous range.
+type jsonChange struct {
+	Login         string            `json:"login"`
+	Name          string            `json:"name"`
+	Team          string            `json:"team"`
+	Bot           bool              `json:"bot"`
+	Status        string            `json:"status"`
+	Previous      jsonStats         `json:"previous"`
+	Current       jsonStats         `json:"current"`
+	Change        jsonStats         `json:"change"`
+	PercentChange jsonPercentChange `json:"percent_change"`
+}
+
+type jsonPercentChange struct {
+	Commits   *float64 `json:"commits"`
+	Additions *float64 `json:"additions"`
+	Deletions *float64 `json:"deletions"`
+}
+
+func printComparisonJSON(out io.Writer, cmp app.Comparison) error {
+	res := jsonComparison{
+		SchemaVersion: jsonSchemaVersion,
+		Repos:         cmp.Current.Repos,
+		Previous:      jsonRange{From: cmp.Previous.From.UTC(), To: cmp.Previous.To.UTC()},
+		Current:       jsonRange{From: cmp.Current.From.UTC(), To: cmp.Current.To.UTC()},
+		GeneratedAt:   cmp.Current.GeneratedAt.UTC(),
+		GroupBy:       cmp.Current.GroupBy,
+		Contributors:  make([]jsonChange, 0, len(cmp.Changes)),
+	}
+	if res.Repos == nil {
+		res.Repos = []string{}
+	}
+	if res.GroupBy == "" {
+		res.GroupBy = app.GroupByContributor
+	}
+
+	percent := func(previous, current int) *float64 {
+		if pc, ok := app.PercentChange(previous, current); ok {
+			return &pc
+		}
+		return nil
+	}
+	for _, c := range cmp.Changes {
+		res.Contributors = append(res.Contributors, jsonChange{
+			Login:    c.Name,
+			Name:     c.DisplayName,
+			Team:     c.Team,
+			Bot:      c.Bot,
+			Status:   c.Status,
+			Previous: newJSONStats(c.Previous),
+			Current:  newJSONStats(c.Current),
+			Change:   newJSONStats(c.Delta()),
+			PercentChange: jsonPercentChange{
+				Commits:   percent(c.Previous.Commits, c.Current.Commits),
+				Additions: percent(c.Previous.Additions, c.Current.Additions),
+				Deletions: percent(c.Previous.Deletions, c.Current.Deletions),
+			},
+		})
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+// printComparisonCSV writes a header row followed by a row per contributor, using comma to separate the fields.
+// Each stat has a column for the previous range, the current range, the change and the percentage change.
+func printComparisonCSV(out io.Writer, cmp app.Comparison, comma rune) error {
+	w := csv.NewWriter(out)
+	w.Comma = comma
+
+	header := []string{"login", "status"}
+	if cmp.Current.GroupBy == app.GroupByTeam {
+		header[0] = "team"
+	}
+	for _, stat := range []string{"commits", "additions", "deletions"} {
+		header = append(header, "previous_"+stat, "current_"+stat, stat+"_change", stat+"_change_percent")
+	}
+	w.Write(header)
+
+	for _, c := range cmp.Changes {
+		row := []string{c.Name, c.Status}
+		for _, pc := range [][2]int{
+			{c.Previous.Commits, c.Current.Commits},
+			{c.Previous.Additions, c.Current.Additions},
+			{c.Previous.Deletions, c.Current.Deletions},
+		} {
+			row = append(row, strconv.Itoa(pc[0]), strconv.Itoa(pc[1]), strconv.Itoa(pc[1]-pc[0]), formatPercentChange(pc[0], pc[1]))
+		}
+		w.Write(row)
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// printComparisonMarkdown writes a GitHub flavoured Markdown table of the changes, with a title line
+func printComparisonMarkdown(out io.Writer, cmp app.Comparison) {
+	fmt.Fprintf(out, "### Contributors to %s %s compared with %s\n\n",
+		strings.Join(cmp.Current.Repos, ", "),
+		describeRange(cmp.Current.From, cmp.Current.To),
+		describeRange(cmp.Previous.From, cmp.Previous.To),
+	)
+
+	title := "Contributor"
+	if cmp.Current.GroupBy == app.GroupByTeam {
+		title = "Team"
+	}
+	fmt.Fprintf(out, "| %s | Commits | Additions | Deletions | Status |\n| --- | --: | --: | --: | --- |\n", title)
+	for _, c := range cmp.Changes {
+		fmt.Fprintf(out, "| %s | %s | %s | %s | %s |\n",
+			escapeMarkdown(c.Name),
+			formatChange(c.Previous.Commits, c.Current.Commits),
+			formatChange(c.Previous.Additions, c.Current.Additions),
+			formatChange(c.Previous.Deletions, c.Current.Deletions),
+			statusLabels[c.Status],
+		)
+	}
+}
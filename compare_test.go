@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+// testComparison is the comparison used by the compare output tests
+var testComparison = app.Comparison{
+	Previous: app.Report{
+		Repos: []string{"test-owner/test-repo"},
+		From:  time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+		To:    time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC),
+	},
+	Current: app.Report{
+		Repos:       []string{"test-owner/test-repo"},
+		From:        time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC),
+		GeneratedAt: time.Date(2018, 10, 2, 9, 0, 0, 0, time.UTC),
+	},
+	Changes: []app.Change{
+		{Name: "test-user-1", Previous: app.Stats{Commits: 8, Additions: 100, Deletions: 20}, Current: app.Stats{Commits: 10, Additions: 50, Deletions: 20}, Status: app.StatusActive},
+		{Name: "test-user-2", Current: app.Stats{Commits: 1, Additions: 5, Deletions: 0}, Status: app.StatusNew},
+		{Name: "test-user-3", Previous: app.Stats{Commits: 2, Additions: 10, Deletions: 4}, Status: app.StatusInactive},
+	},
+}
+
+func TestPrintComparison(t *testing.T) {
+	ts := []struct {
+		Name   string
+		Format string
+		Expect string
+	}{
+		{
+			Name:   "Text",
+			Format: formatText,
+			Expect: `Previous: from 2018-04-01 to 2018-07-01
+Current: from 2018-07-01 to 2018-10-01
+Contributor: test-user-1   Commits: 8 → 10 (+2, +25.0%)   Additions: 100 → 50 (-50, -50.0%)   Deletions: 20 → 20 (+0, +0.0%)   
+Contributor: test-user-2   Commits: 0 → 1 (+1)            Additions: 0 → 5 (+5)               Deletions: 0 → 0 (+0)            newly active
+Contributor: test-user-3   Commits: 2 → 0 (-2, -100.0%)   Additions: 10 → 0 (-10, -100.0%)    Deletions: 4 → 0 (-4, -100.0%)   no longer active
+`,
+		},
+		{
+			Name:   "CSV",
+			Format: formatCSV,
+			Expect: `login,status,previous_commits,current_commits,commits_change,commits_change_percent,previous_additions,current_additions,additions_change,additions_change_percent,previous_deletions,current_deletions,deletions_change,deletions_change_percent
+test-user-1,active,8,10,2,25.0,100,50,-50,-50.0,20,20,0,0.0
+test-user-2,new,0,1,1,,0,5,5,,0,0,0,
+test-user-3,inactive,2,0,-2,-100.0,10,0,-10,-100.0,4,0,-4,-100.0
+`,
+		},
+		{
+			Name:   "Markdown",
+			Format: formatMarkdown,
+			Expect: `### Contributors to test-owner/test-repo from 2018-07-01 to 2018-10-01 compared with from 2018-04-01 to 2018-07-01
+
+| Contributor | Commits | Additions | Deletions | Status |
+| --- | --: | --: | --: | --- |
+| test-user-1 | 8 → 10 (+2, +25.0%) | 100 → 50 (-50, -50.0%) | 20 → 20 (+0, +0.0%) |  |
+| test-user-2 | 0 → 1 (+1) | 0 → 5 (+5) | 0 → 0 (+0) | newly active |
+| test-user-3 | 2 → 0 (-2, -100.0%) | 10 → 0 (-10, -100.0%) | 4 → 0 (-4, -100.0%) | no longer active |
+`,
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := printComparison(&buf, testComparison, processedInputs{Format: tc.Format}); err != nil {
+				t.Fatalf("printComparison: Unexpected Error: %v", err)
+			}
+			if buf.String() != tc.Expect {
+				t.Errorf("printComparison:\n\nhave result:\n%s\n\nwant result:\n%s", buf.String(), tc.Expect)
+			}
+		})
+	}
+}
+
+func TestPrintComparisonJSON(t *testing.T) {
+	cmp := testComparison
+	cmp.Changes = cmp.Changes[1:2]
+
+	expect := `{
+  "schema_version": 1,
+  "repos": [
+    "test-owner/test-repo"
+  ],
+  "previous": {
+    "from": "2018-04-01T00:00:00Z",
+    "to": "2018-07-01T00:00:00Z"
+  },
+  "current": {
+    "from": "2018-07-01T00:00:00Z",
+    "to": "2018-10-01T00:00:00Z"
+  },
+  "generated_at": "2018-10-02T09:00:00Z",
+  "group_by": "contributor",
+  "contributors": [
+    {
+      "login": "test-user-2",
+      "name": "",
+      "team": "",
+      "bot": false,
+      "status": "new",
+      "previous": {
+        "commits": 0,
+        "additions": 0,
+        "deletions": 0
+      },
+      "current": {
+        "commits": 1,
+        "additions": 5,
+        "deletions": 0
+      },
+      "change": {
+        "commits": 1,
+        "additions": 5,
+        "deletions": 0
+      },
+      "percent_change": {
+        "commits": null,
+        "additions": null,
+        "deletions": null
+      }
+    }
+  ]
+}
+`
+	var buf bytes.Buffer
+	if err := printComparisonJSON(&buf, cmp); err != nil {
+		t.Fatalf("printComparisonJSON: Unexpected Error: %v", err)
+	}
+	if buf.String() != expect {
+		t.Errorf("printComparisonJSON:\n\nhave result:\n%s\n\nwant result:\n%s", buf.String(), expect)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// readConfig decodes the config file into v. Files ending in .json are read as JSON, anything else as YAML.
+// Unknown keys are an error, to catch typos.
+func readConfig(file string, v interface{}) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return errors.Wrap(err, "[readConfig] could not read file")
+	}
+
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(v)
+	} else {
+		err = yaml.UnmarshalStrict(b, v)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "[readConfig] invalid %s", file)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/pkg/errors"
+)
+
+// loadExclusions returns the contributors to leave out, reading the login lists given by inputs
+func loadExclusions(inputs processedInputs) (app.Exclusions, error) {
+	res := app.Exclusions{Bots: inputs.ExcludeBots}
+	var err error
+	if inputs.IncludeLogins != "" {
+		if res.Include, err = readLogins(inputs.IncludeLogins); err != nil {
+			return app.Exclusions{}, err
+		}
+		// otherwise nobody would be left out, which surely isn't what was meant
+		if len(res.Include) == 0 {
+			return app.Exclusions{}, errors.Errorf("[loadExclusions] %s doesn't list any logins to include", inputs.IncludeLogins)
+		}
+	}
+	if inputs.ExcludeLogins != "" {
+		if res.Exclude, err = readLogins(inputs.ExcludeLogins); err != nil {
+			return app.Exclusions{}, err
+		}
+	}
+	return res, nil
+}
+
+// readLogins reads a file of logins, one per line.
+// Surrounding whitespace, blank lines and comments (from # to the end of the line) are ignored.
+func readLogins(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "[readLogins] could not read login list")
+	}
+	defer f.Close()
+
+	var res []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			res = append(res, line)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, errors.Wrap(err, "[readLogins] could not read login list")
+	}
+	return res, nil
+}
+
+// describeExcluded returns the summary line of the contributors that were left out
+func describeExcluded(e app.Excluded) string {
+	bots := ""
+	if e.Bots > 0 {
+		bots = fmt.Sprintf(" (%s)", plural(e.Bots, "bot"))
+	}
+	return fmt.Sprintf(
+		"excluded %s%s with commits: %s, additions: %s, deletions: %s",
+		plural(e.Contributors, "contributor"), bots,
+		formatNumber(e.Stats.Commits), formatNumber(e.Stats.Additions), formatNumber(e.Stats.Deletions),
+	)
+}
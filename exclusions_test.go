@@ -0,0 +1,97 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestLoadExclusions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gh-contrib-stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, text string) string {
+		file := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(file, []byte(text), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	logins := write("logins.txt", "# service accounts\nci-user\n\n  deploy-bot   # the deploy pipeline\n")
+	empty := write("empty.txt", "# nobody yet\n")
+
+	ts := []struct {
+		Name      string
+		Inputs    processedInputs
+		ExpectRes app.Exclusions
+		ExpectErr string
+	}{
+		{
+			Name:      "Bots And Exclude List",
+			Inputs:    processedInputs{ExcludeBots: true, ExcludeLogins: logins},
+			ExpectRes: app.Exclusions{Bots: true, Exclude: []string{"ci-user", "deploy-bot"}},
+		},
+		{
+			Name:      "Include List",
+			Inputs:    processedInputs{IncludeLogins: logins},
+			ExpectRes: app.Exclusions{Include: []string{"ci-user", "deploy-bot"}},
+		},
+		{
+			Name:      "Empty Include List",
+			Inputs:    processedInputs{IncludeLogins: empty},
+			ExpectErr: "[loadExclusions] " + empty + " doesn't list any logins to include",
+		},
+		{
+			Name:      "Missing File",
+			Inputs:    processedInputs{ExcludeLogins: filepath.Join(dir, "missing.txt")},
+			ExpectErr: "[readLogins] could not read login list",
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			res, err := loadExclusions(tc.Inputs)
+			if tc.ExpectErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.ExpectErr) {
+					t.Fatalf("loadExclusions: Have `err`: %v want: %s", err, tc.ExpectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadExclusions: Unexpected Error: %v", err)
+			}
+			if !reflect.DeepEqual(res, tc.ExpectRes) {
+				t.Errorf("loadExclusions:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, tc.ExpectRes)
+			}
+		})
+	}
+}
+
+func TestDescribeExcluded(t *testing.T) {
+	ts := []struct {
+		Excluded app.Excluded
+		Expect   string
+	}{
+		{
+			Excluded: app.Excluded{Contributors: 3, Bots: 2, Stats: app.Stats{Commits: 1200, Additions: 50000, Deletions: 3}},
+			Expect:   "excluded 3 contributors (2 bots) with commits: 1,200, additions: 50,000, deletions: 3",
+		},
+		{
+			Excluded: app.Excluded{Contributors: 1, Stats: app.Stats{Commits: 1}},
+			Expect:   "excluded 1 contributor with commits: 1, additions: 0, deletions: 0",
+		},
+	}
+
+	for _, tc := range ts {
+		if res := describeExcluded(tc.Excluded); res != tc.Expect {
+			t.Errorf("describeExcluded: have %q want %q", res, tc.Expect)
+		}
+	}
+}
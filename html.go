@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/luke-davies/gh-contrib-stats/pkg/chart"
+)
+
+const (
+	// the commits chart stacks this many contributors, the rest are stacked together as "Others"
+	htmlChartContributors = 9
+	// the additions/deletions chart only has the contributors who changed the most lines
+	htmlBarsContributors = 25
+)
+
+// printHTML writes a self-contained HTML report, with no external resources, made up of:
+// a sortable table of the contributors, a stacked chart of the commits per week and the additions/deletions per contributor.
+// With an interval, a table of the metric per period of each contributor follows the first table.
+func printHTML(out io.Writer, r app.Report, metric string) error {
+	commits, err := weeklyCommitsChart(r)
+	if err != nil {
+		return err
+	}
+	changes, err := changesChart(r)
+	if err != nil {
+		return err
+	}
+
+	var m *matrix
+	if r.Interval != "" {
+		mx := newMatrix(r, metric)
+		m = &mx
+	}
+
+	return htmlTemplate.Execute(out, struct {
+		app.Report
+		Range            string
+		CommitsChart     template.HTML
+		ChangesChart     template.HTML
+		BarsContributors int
+		Matrix           *matrix
+	}{
+		Report:           r,
+		Range:            describeRange(r.From, r.To),
+		CommitsChart:     commits,
+		ChangesChart:     changes,
+		BarsContributors: htmlBarsContributors,
+		Matrix:           m,
+	})
+}
+
+// weeklyCommitsChart returns an SVG of the commits per week, stacked per contributor
+func weeklyCommitsChart(r app.Report) (template.HTML, error) {
+	weeks := r.Weeks()
+	top, rest := topContributors(r.Contributors, htmlChartContributors, func(s app.Stats) int { return s.Commits })
+	var series []chart.Series
+	for _, c := range top {
+		series = append(series, chart.Series{Name: c.Name, Values: weeklyCommits(c, weeks)})
+	}
+	if len(rest) > 0 {
+		others := chart.Series{Name: "Others", Values: make([]float64, len(weeks))}
+		for _, c := range rest {
+			for i, v := range weeklyCommits(c, weeks) {
+				others.Values[i] += v
+			}
+		}
+		series = append(series, others)
+	}
+
+	var buf bytes.Buffer
+	err := chart.StackedBars(&buf, chart.Options{Title: "Commits per week"}, weekLabels(weeks), series)
+	return template.HTML(buf.String()), err
+}
+
+// changesChart returns an SVG of the additions and deletions of the contributors who changed the most lines
+func changesChart(r app.Report) (template.HTML, error) {
+	top, _ := topContributors(r.Contributors, htmlBarsContributors, func(s app.Stats) int { return s.Additions + s.Deletions })
+
+	labels := make([]string, len(top))
+	additions := chart.Series{Name: "Additions", Values: make([]float64, len(top)), Colour: "#2cbe4e"}
+	deletions := chart.Series{Name: "Deletions", Values: make([]float64, len(top)), Colour: "#cb2431"}
+	for i, c := range top {
+		labels[i] = c.Name
+		additions.Values[i] = float64(c.Stats.Additions)
+		deletions.Values[i] = float64(c.Stats.Deletions)
+	}
+
+	var buf bytes.Buffer
+	err := chart.Bars(&buf, chart.Options{Title: "Additions and deletions"}, labels, []chart.Series{additions, deletions})
+	return template.HTML(buf.String()), err
+}
+
+func weeklyCommits(c app.Contributor, weeks []time.Time) []float64 {
+	stats := c.WeeklyStats(weeks)
+	res := make([]float64, len(stats))
+	for i, s := range stats {
+		res[i] = float64(s.Commits)
+	}
+	return res
+}
+
+// topContributors returns the n contributors with the highest key and the rest.
+// Contributors with the same key keep their order.
+func topContributors(cs []app.Contributor, n int, key func(app.Stats) int) ([]app.Contributor, []app.Contributor) {
+	sorted := append([]app.Contributor(nil), cs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return key(sorted[i].Stats) > key(sorted[j].Stats) })
+	if len(sorted) <= n {
+		return sorted, nil
+	}
+	return sorted[:n], sorted[n:]
+}
+
+var htmlTemplate = template.Must(template.New("html").Funcs(template.FuncMap{"join": strings.Join, "decimal": formatDecimal}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Contributors to {{join .Repos ", "}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 900px; color: #24292e; }
+h1 { font-size: 1.5em; }
+h2 { font-size: 1.2em; }
+.meta { color: #586069; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0 2em; }
+th, td { padding: 4px 8px; border-bottom: 1px solid #e1e4e8; text-align: left; }
+th { cursor: pointer; user-select: none; background: #f6f8fa; }
+th.num, td.num { text-align: right; }
+th[aria-sort=ascending]::after { content: " \25B2"; }
+th[aria-sort=descending]::after { content: " \25BC"; }
+tfoot td { font-weight: bold; }
+svg { display: block; margin: 1em 0; max-width: 100%; height: auto; }
+</style>
+</head>
+<body>
+<h1>Contributors to {{join .Repos ", "}}</h1>
+<p class="meta">{{.Range}}. Generated at {{.GeneratedAt.UTC.Format "2006-01-02 15:04 MST"}}.</p>
+
+<table id="contributors">
+<thead>
+<tr><th>{{if eq .GroupBy "team"}}Team{{else}}Contributor{{end}}</th><th class="num">Commits</th><th class="num">Additions</th><th class="num">Deletions</th><th class="num">Net</th><th class="num">Churn</th><th class="num">Lines per commit</th><th class="num">Commit share (%)</th><th class="num">Line share (%)</th></tr>
+</thead>
+<tbody>
+{{- range .Contributors}}
+<tr><td>{{.Name}}</td><td class="num">{{.Stats.Commits}}</td><td class="num">{{.Stats.Additions}}</td><td class="num">{{.Stats.Deletions}}</td><td class="num">{{.Stats.Net}}</td><td class="num">{{.Stats.Churn}}</td><td class="num">{{decimal .Stats.LinesPerCommit}}</td><td class="num">{{decimal .CommitShare}}</td><td class="num">{{decimal .LineShare}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot>
+{{- with .Total}}
+<tr><td>Total</td><td class="num">{{.Commits}}</td><td class="num">{{.Additions}}</td><td class="num">{{.Deletions}}</td><td class="num">{{.Net}}</td><td class="num">{{.Churn}}</td><td class="num">{{decimal .LinesPerCommit}}</td><td></td><td></td></tr>
+{{- end}}
+</tfoot>
+</table>
+
+{{- with .Matrix}}
+
+<h2>{{.Title}}</h2>
+<table>
+<thead>
+<tr><th>{{.Corner}}</th>{{range .Periods}}<th class="num">{{.}}</th>{{end}}<th class="num">Total</th></tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr><td>{{.Name}}</td>{{range .Values}}<td class="num">{{.}}</td>{{end}}<td class="num">{{.Total}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+
+{{.CommitsChart}}
+{{if gt (len .Contributors) .BarsContributors}}<p class="meta">The {{.BarsContributors}} contributors who changed the most lines.</p>{{end}}
+{{.ChangesChart}}
+
+<script>
+(function () {
+  var table = document.getElementById("contributors");
+  var headers = table.tHead.rows[0].cells;
+  Array.prototype.forEach.call(headers, function (th, col) {
+    th.addEventListener("click", function () {
+      var asc = th.getAttribute("aria-sort") !== "ascending";
+      var num = th.classList.contains("num");
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[col].textContent, y = b.cells[col].textContent;
+        var c = num ? x - y : x.localeCompare(y);
+        return asc ? c : -c;
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+      Array.prototype.forEach.call(headers, function (h) { h.removeAttribute("aria-sort"); });
+      th.setAttribute("aria-sort", asc ? "ascending" : "descending");
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
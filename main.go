@@ -4,21 +4,70 @@ package main
 
 import (
 	"context"
-	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"sort"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	"github.com/luke-davies/gh-contrib-stats/pkg/app"
 	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+	"github.com/pkg/errors"
 )
 
 const (
-	githubBaseURL = "https://api.github.com"
+	githubBaseURL   = "https://api.github.com"
+	pollBackoff     = 1.5
+	maxPollInterval = 30 * time.Second
+)
+
+// Commands. The first argument may be one of these, otherwise cmdContributors is used.
+const (
+	cmdContributors  = "contributors"
+	cmdActivity      = "activity"
+	cmdParticipation = "participation"
+	cmdPunchCard     = "punchcard"
+	cmdChart         = "chart"
+	cmdCompare       = "compare"
+	cmdAnalyze       = "analyze"
+)
+
+// commands maps each command to its description, as shown in the usage
+var commands = map[string]string{
+	cmdContributors:  "stats per contributor (default)",
+	cmdActivity:      "repo-wide commits, additions and deletions per week",
+	cmdParticipation: "owner vs. non-owner commits per week, for the last year at most",
+	cmdPunchCard:     "heatmap of commits by day of the week and hour of the day, for the whole history of the repo",
+	cmdChart:         "SVG charts of the contributors: commits per week, cumulative lines changed and the top contributors",
+	cmdCompare:       "change in each contributor's stats since the previous date range, and who is newly active or no longer active",
+	cmdAnalyze:       "bus factor and how concentrated the contributions to each repo are, with their trend over rolling windows",
+}
+
+// now is the time the date range is relative to, replaced by tests that need a fixed date
+var now = time.Now
+
+// Precisions for the contributors command
+const (
+	precisionWeek = "week"
+	precisionDay  = "day"
+)
+
+// Exit codes so that scripts can tell failures apart.
+// exitError is also used for usage errors (and by log.Fatal).
+const (
+	exitError        = 1
+	exitStatsPending = 2
+	exitUnauthorized = 3
+	exitNotFound     = 4
+	exitRateLimited  = 5
+	exitGitHubError  = 6 // any other unsuccessful response from GitHub
+	exitPartial      = 7 // some of the repositories failed, the output only covers the others
 )
 
 func main() {
@@ -26,58 +75,140 @@ func main() {
 	if err != nil {
 		log.Print(err) // would log.Fatal but want usage after error
 		flag.Usage()
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
 	inputs, err := processInput(raw)
 	if err != nil {
 		log.Print(err)
 		flag.Usage()
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
-	// TODO: Move more of this into other functions to make it easier to test
-	ghClient := github.Client{BaseURL: githubBaseURL}
-
-	gcs, err := ghClient.ListContributorStats(context.Background(), inputs.Owner, inputs.Repo)
+	httpClient, err := newHTTPClient(inputs)
 	if err != nil {
-		// usage probably not helpful if they make it this far..
 		log.Fatal(err.Error())
 	}
 
-	var acs []app.Contributor
-	opts := app.NormaliseCalcContributionsOpts(app.CalcContrbutionsOpts{From: inputs.From, To: inputs.To})
-	err = app.ValidateCalcContributionsOpts(opts)
-	if err != nil {
-		log.Fatal(err.Error())
+	// TODO: Move more of this into other functions to make it easier to test
+	ghClient := github.Client{
+		BaseURL:    inputs.APIURL,
+		HTTPClient: httpClient,
+		Poll: github.PollOpts{
+			Interval:    inputs.PollInterval,
+			Backoff:     pollBackoff,
+			MaxInterval: maxPollInterval,
+			Timeout:     inputs.PollTimeout,
+		},
+		RateLimit: github.RateLimitOpts{
+			State: &github.RateLimitState{},
+			Wait:  inputs.WaitForRateLimit,
+			OnWait: func(until time.Time) {
+				fmt.Fprintf(os.Stderr, "GitHub rate limit reached, waiting until %s\n", until.Format("15:04:05"))
+			},
+		},
 	}
 
-	for _, gc := range *gcs {
-		c := app.CalcContributions(gc, opts)
-		acs = append(acs, c)
+	err = run(context.Background(), ghClient, inputs)
+	if err != nil {
+		// usage probably not helpful if they make it this far..
+		log.Print(err)
+		os.Exit(exitCode(err))
 	}
+}
 
-	if !inputs.All {
-		acs = app.FilterContributors(acs, func(ac app.Contributor) bool { return ac.Stats.Commits > 0 })
+// run runs the given command
+func run(ctx context.Context, client github.Client, inputs processedInputs) error {
+	switch inputs.Command {
+	case cmdActivity:
+		return runActivity(ctx, client, inputs)
+	case cmdParticipation:
+		return runParticipation(ctx, client, inputs)
+	case cmdPunchCard:
+		return runPunchCard(ctx, client, inputs)
+	case cmdChart:
+		return runChart(ctx, client, inputs)
+	case cmdCompare:
+		return runCompare(ctx, client, inputs)
+	case cmdAnalyze:
+		return runAnalyze(ctx, client, inputs)
+	default:
+		return runContributors(ctx, client, inputs)
 	}
-
-	printStats(acs)
-
 }
 
 // simplifies parseInput signature
 type rawInputs struct {
-	Repo   string
+	Command string
+
+	Repos           []string
+	Org             string
+	IncludeForks    bool
+	IncludeArchived bool
+	PerRepo         bool
+	PerWeek         bool
+	Format          string
+
+	Columns      string
+	LinkProfiles bool
+
+	Template       string
+	TemplateString string
+
+	OutDir string
+	Top    int
+	Sort   string
+
+	Spark bool
+	Bars  bool
+
 	From   string
 	To     string
 	Weeks  int
 	Months int
 	Years  int
 	All    bool
+	Where  string
+
+	CompareFrom string
+	CompareTo   string
+
+	ExcludeBots   bool
+	IncludeLogins string
+	ExcludeLogins string
+	Aliases       string
+
+	GroupBy     string
+	Teams       string
+	GitHubTeams string
+
+	Interval string
+	Metric   string
+	Coverage float64
+	Window   int
+
+	Precision string
+	Parallel  int
+
+	PollInterval time.Duration
+	PollTimeout  time.Duration
+
+	Token             string
+	AppID             int64
+	AppInstallationID int64
+	AppPrivateKey     string
+
+	WaitForRateLimit bool
+
+	APIURL     string
+	CACert     string
+	ClientCert string
+	ClientKey  string
 }
 
-// ParseInput parses flags and returns relevant 'repo-owner', `repo-name`, from`, `to` and `all`.
-// - repoOwner and repoName idenitfy the GitHub repository.
+// ParseInput parses the command and flags and returns relevant `repos`, `from`, `to` and `all`.
+// - command is the optional first argument. See commands.
+// - repos idenitfy the GitHub repositories, in the form owner/name. The name can be a pattern e.g. owner/*.
 // - from & to specify the date range.
 // - all specifies whether to include contributors who have no contributions during the specified date range.
 //
@@ -88,57 +219,286 @@ func parseInput() (rawInputs, error) {
 	weeks := flag.Int("weeks", 0, "Set lower bound by number of weeks. Can be combined with --months and --years. Zero is ignored. Can not be used with --from and --to.")
 	months := flag.Int("months", 0, "Set lower bound by number of months. Can be combined with --weeks and --years. Zero is ignored. Can not be used with --from and --to.")
 	years := flag.Int("years", 0, "Set lower bound by number of years. Can be combined with --weeks and --months. Zero is ignored. Can not be used with --from and --to.")
+	compareFrom := flag.String("compare-from", "", "Lower bound (inclusive) of the previous date range of the compare command. Format: `YYYY-MM-DD`. Requires --compare-to. "+
+		"Defaults to the range just before the date range: the same number of months if it's a whole number of months (e.g. a quarter), otherwise the same length.")
+	compareTo := flag.String("compare-to", "", "Upper bound (exclusive) of the previous date range of the compare command. Format: `YYYY-MM-DD`. Requires --compare-from.")
 	all := flag.Bool("all", false, "Show all contributors regardless of whether they have made contributions during the specified date range. By default, contributors without contributions in the date range are omitted.")
+	where := flag.String("where", "", "Only include contributors matching the `expression` e.g. 'commits >= 5 && login !~ \"bot]$\"'. "+
+		"Compare commits, additions, deletions, net, churn, lines_per_commit, commit_share and line_share with ==, !=, <, <=, > and >=, and login with ==, != or the regular expression operators =~ and !~. "+
+		"Combine comparisons with &&, ||, ! and parentheses. For the compare command, contributors matching in either date range are kept in both.")
+	excludeBots := flag.Bool("exclude-bots", false, "Leave out bot accounts i.e. GitHub says they're a Bot or their login ends in [bot], like dependabot[bot]. How many contributions were left out is written to stderr.")
+	includeLogins := flag.String("include-logins", "", "Path to a file of logins, one per line, to only include. Listed logins are kept even with --exclude-bots. # starts a comment.")
+	excludeLogins := flag.String("exclude-logins", "", "Path to a file of logins, one per line, to leave out e.g. CI or other service accounts. Wins over --include-logins. # starts a comment.")
+	aliases := flag.String("aliases", "", "Path to a YAML or JSON file mapping the logins of people with several accounts to one login, display name and team. Their stats are merged before filtering and sorting. See the README for the format.")
+	groupBy := flag.String("group-by", app.GroupByContributor, "Granularity of the contributors command: `contributor` or `team`. With team, the contributors are rolled up into a line per team of --teams, --github-teams and the teams of --aliases, with a breakdown per member.")
+	teams := flag.String("teams", "", "Path to a YAML or JSON file of the members of each team, for --group-by team. See the README for the format.")
+	githubTeams := flag.String("github-teams", "", "Comma separated GitHub teams to get the members of, for --group-by team e.g. `golang/core,golang/tools`. Uses the team slug. Needs read:org access for secret teams.")
+	interval := flag.String("interval", "", "Break each contributor's stats down per `week|month|quarter|year` of the date range, as a contributor × period table in every --format. "+
+		"A week is counted in the month, quarter or year its week beginning (a Sunday) is in. For the analyze command, the trend has a rolling window ending at the end of each period.")
+	metric := flag.String("metric", app.SortCommits, "The stat in each period of --interval: `commits`, additions, deletions, net or churn. For the analyze command, the stat the bus factor and concentration are measured by, which can't be net.")
+	coverage := flag.Float64("coverage", defaultCoverage, "The `percent` of the --metric the bus factor of the analyze command covers: the fewest contributors with at least that share between them.")
+	window := flag.Int("window", defaultWindow, "Length in `weeks` of the rolling windows of the analyze command's trend, see --interval.")
+	org := flag.String("org", "", "Include every repository of the given organisation, except forks and archived repositories. Can be combined with repository arguments.")
+	includeForks := flag.Bool("include-forks", false, "Include forks in the repositories of --org and of patterns like owner/*. Forks named as arguments are always included.")
+	includeArchived := flag.Bool("include-archived", false, "Include archived repositories in the repositories of --org and of patterns like owner/*. Archived repositories named as arguments are always included.")
+	format := flag.String("format", formatText, "Output format of the contributors command: `"+strings.Join(formats, "|")+"`.")
+	perRepo := flag.Bool("per-repo", false, "Show a breakdown of each contributor's stats per repository.")
+	cols := flag.String("columns", "", "Comma separated columns of the text output and markdown table, in order, from: "+strings.Join(columnNames(), ",")+". Defaults to "+strings.Join(defaultColumnNames(), ",")+".")
+	linkProfiles := flag.Bool("link-profiles", false, "In the markdown table, link each contributor to their GitHub profile.")
+	tmpl := flag.String("template", "", "Path to a Go text/template to write the contributors with, instead of --format. See the README for the data model and helper functions.")
+	tmplString := flag.String("template-string", "", "Like --template but the template is given inline e.g. '{{range .Contributors}}{{println .Name .Stats.Commits}}{{end}}'.")
+	outDir := flag.String("out-dir", ".", "Directory the chart command writes its SVG files to.")
+	top := flag.Int("top", 0, fmt.Sprintf("Only show the first N contributors, after sorting. Zero shows all. For the chart command, the number of contributors in each chart; zero uses %d.", defaultChartTop))
+	sortBy := flag.String("sort", "", "Comma separated fields to sort the contributors by, in order e.g. `commits,-additions`. Prefix a field with - for descending order. "+
+		"Fields: commits, additions, deletions, net (additions - deletions), churn (additions + deletions), lines_per_commit (churn / commits), "+
+		"commit_share and line_share (percentages of the commits and churn of the repos in the date range) and name. Contributors that are equal are always sorted by name.")
+	spark := flag.Bool("spark", false, "In the text output, add a sparkline of each contributor's commits per week.")
+	bars := flag.Bool("bars", false, "In the text output, add a bar of each contributor's commits. The bars fit the width of the terminal (or $COLUMNS).")
+	perWeek := flag.Bool("per-week", false, "With --format csv or tsv, write a row per contributor per week instead of their totals. Weeks without contributions are left out.")
+	precision := flag.String("precision", precisionWeek, "Precision of the date range for the contributors command. `week` uses GitHub's weekly contributor stats. `day` applies the range to the exact time of each commit but needs a request per commit, so is much slower. With `day`, --all has no effect.")
+	parallel := flag.Int("parallel", 4, "How many repositories to fetch at once, and with --precision day how many commits of each. A failure for one repository doesn't stop the others; the failures are listed at the end.")
+	pollInterval := flag.Duration("poll-interval", 2*time.Second, "How long to wait before asking GitHub again when the stats are still being computed (202). The wait backs off between attempts. Zero disables polling.")
+	pollTimeout := flag.Duration("poll-timeout", 2*time.Minute, "Give up waiting for GitHub to compute the stats after this long, not counting waits for rate limits. Zero waits indefinitely.")
+	token := flag.String("token", "", "GitHub personal access token used to authenticate requests. Defaults to the GITHUB_TOKEN environment variable. Ignored when using the --app-* options.")
+	appID := flag.Int64("app-id", 0, "Authenticate as a GitHub App with this ID. Requires --app-installation-id and --app-private-key.")
+	appInstallationID := flag.Int64("app-installation-id", 0, "Installation ID of the GitHub App. Requires --app-id and --app-private-key.")
+	appPrivateKey := flag.String("app-private-key", "", "Path to the PEM encoded private key of the GitHub App. Requires --app-id and --app-installation-id.")
+	apiURL := flag.String("api-url", "", "Root of the GitHub API e.g. `https://[hostname]/api/v3` for GitHub Enterprise. Defaults to the GITHUB_API_URL environment variable or "+githubBaseURL+".")
+	caCert := flag.String("ca-cert", "", "Path to a PEM bundle of extra CA certificates to trust e.g. for GitHub Enterprise with an internal CA.")
+	clientCert := flag.String("client-cert", "", "Path to a PEM client certificate, for servers that require one. Requires --client-key.")
+	clientKey := flag.String("client-key", "", "Path to the PEM key of --client-cert.")
+	waitForRateLimit := flag.Bool("wait-for-rate-limit", false, "When the GitHub rate limit is reached, wait for it to reset and carry on. By default the command fails. "+
+		"Waits at least a minute, doubling after each retry, and gives up after 5 retries.")
 
 	flag.Usage = func() {
 		fmt.Fprintf(
 			flag.CommandLine.Output(),
-			"Usage of %[1]s: %[1]s [date range options] [owner]/[repo]\n\n"+
-				"Retrieves contributor stats for a repository for the given date range.\n"+
+			"Usage of %[1]s: %[1]s [command] [options] [owner]/[repo]...\n\n"+
+				"Retrieves stats for one or more repositories for the given date range.\n"+
+				"Repository names can be patterns e.g. 'my-org/*' or 'my-org/api-*'. Stats for several repositories are merged per contributor.\n"+
 				"This uses the GitHub API, which groups stats by week beginning. Therefore, stats for yesterday may not appear if the "+
-				"beginning of the week is not within the date range.\n"+
-				"Contributors with 0 commits in the given date range are filtered out.\n\n"+
+				"beginning of the week is not within the date range. Use --precision day for exact ranges.\n"+
+				"Contributors with 0 commits in the given date range are filtered out.\n"+
+				"If GitHub is still computing the stats (202) the request is retried until --poll-timeout.\n\n"+
+				"Exit codes:\n"+
+				"\t1 usage or unexpected error\n"+
+				"\t2 GitHub didn't finish computing the stats in time\n"+
+				"\t3 unauthorized (401/403)\n"+
+				"\t4 repository not found (or no access to it)\n"+
+				"\t5 rate limited\n"+
+				"\t6 any other error response from GitHub\n"+
+				"\t7 some of the repositories failed, the output only covers the others\n"+
+				"When every repository fails with the same class of error, its exit code is used.\n\n"+
+				"Commands:\n"+
+				"%[2]s\n"+
 				"Examples:\n"+
 				"\t%[1]s golang/go\n"+
 				"\t%[1]s --from 2017-09-01 --to 2018-02-01 golang/go\n"+
-				"\t%[1]s --weeks 10 golang/go\n\n"+
-				"Options for date range:\n\n",
-			os.Args[0],
+				"\t%[1]s --weeks 10 golang/go\n"+
+				"\t%[1]s golang/go golang/tools 'golang/x*'\n"+
+				"\t%[1]s --org golang --per-repo\n"+
+				"\t%[1]s --sort -commits,name --top 10 golang/go\n"+
+				"\t%[1]s --exclude-bots --exclude-logins service-accounts.txt golang/go\n"+
+				"\t%[1]s --aliases people.yml golang/go\n"+
+				"\t%[1]s --group-by team --github-teams golang/core,golang/tools golang/go\n"+
+				"\t%[1]s --where 'commits >= 5 && login !~ \"bot]$\"' golang/go\n"+
+				"\t%[1]s --interval month --metric churn --months 6 golang/go\n"+
+				"\t%[1]s --spark --bars --months 6 golang/go\n"+
+				"\t%[1]s --format json golang/go\n"+
+				"\t%[1]s --format csv --per-week --weeks 10 golang/go\n"+
+				"\t%[1]s --format markdown --link-profiles --columns login,commits golang/go\n"+
+				"\t%[1]s --format html --months 3 golang/go > report.html\n"+
+				"\t%[1]s --template-string '{{range .Contributors}}{{println .Name (number .Stats.Additions)}}{{end}}' golang/go\n"+
+				"\t%[1]s --precision day --from 2018-06-18 --to 2018-06-23 golang/go\n"+
+				"\t%[1]s activity --weeks 10 golang/go\n"+
+				"\t%[1]s punchcard golang/go\n"+
+				"\t%[1]s chart --months 6 --top 5 --out-dir docs golang/go\n"+
+				"\t%[1]s compare --from 2018-07-01 --to 2018-10-01 golang/go\n"+
+				"\t%[1]s analyze --metric churn --coverage 80 --months 12 --interval month golang/go golang/tools\n\n"+
+				"Options:\n\n",
+			os.Args[0], commandsUsage(),
 		)
 		flag.PrintDefaults()
 	}
 
-	flag.Parse()
+	args := os.Args[1:]
+	command := cmdContributors
+	if len(args) > 0 {
+		if _, ok := commands[args[0]]; ok {
+			command, args = args[0], args[1:]
+		}
+	}
 
-	if flag.NArg() > 1 {
-		// because flag.Parse() cant find flags after the args...
-		return rawInputs{}, errors.New("[checkFlags] only one argument expected and all flags should be specified before repository argument")
+	flag.CommandLine.Parse(args)
+
+	repos := flag.Args()
+	for _, repo := range repos {
+		if strings.HasPrefix(repo, "-") {
+			// because flag.Parse() cant find flags after the args...
+			return rawInputs{}, errors.New("[checkFlags] all flags should be specified before repository arguments")
+		}
 	}
 
-	repo := flag.Arg(0)
+	if *token == "" {
+		*token = os.Getenv("GITHUB_TOKEN")
+	}
 
-	if repo == "" {
-		return rawInputs{}, errors.New("[checkFlags] repository must be specified")
+	if *apiURL == "" {
+		*apiURL = os.Getenv("GITHUB_API_URL")
+	}
+
+	if len(repos) == 0 && *org == "" {
+		return rawInputs{}, errors.New("[checkFlags] repository or --org must be specified")
 	}
 
 	return rawInputs{
-		Repo:   repo,
+		Command: command,
+
+		Repos:           repos,
+		Org:             *org,
+		IncludeForks:    *includeForks,
+		IncludeArchived: *includeArchived,
+		PerRepo:         *perRepo,
+		PerWeek:         *perWeek,
+		Format:          *format,
+
+		Columns:      *cols,
+		LinkProfiles: *linkProfiles,
+
+		Template:       *tmpl,
+		TemplateString: *tmplString,
+
+		OutDir: *outDir,
+		Top:    *top,
+		Sort:   *sortBy,
+
+		Spark: *spark,
+		Bars:  *bars,
+
 		From:   *from,
 		To:     *to,
 		Years:  *years,
 		Months: *months,
 		Weeks:  *weeks,
 		All:    *all,
+		Where:  *where,
+
+		CompareFrom: *compareFrom,
+		CompareTo:   *compareTo,
+
+		ExcludeBots:   *excludeBots,
+		IncludeLogins: *includeLogins,
+		ExcludeLogins: *excludeLogins,
+		Aliases:       *aliases,
+
+		GroupBy:     *groupBy,
+		Teams:       *teams,
+		GitHubTeams: *githubTeams,
+
+		Interval: *interval,
+		Metric:   *metric,
+		Coverage: *coverage,
+		Window:   *window,
+
+		Precision: *precision,
+		Parallel:  *parallel,
+
+		PollInterval: *pollInterval,
+		PollTimeout:  *pollTimeout,
+
+		Token:             *token,
+		AppID:             *appID,
+		AppInstallationID: *appInstallationID,
+		AppPrivateKey:     *appPrivateKey,
+
+		WaitForRateLimit: *waitForRateLimit,
+
+		APIURL:     *apiURL,
+		CACert:     *caCert,
+		ClientCert: *clientCert,
+		ClientKey:  *clientKey,
 	}, nil
 }
 
 // simplifies processInput signature
 type processedInputs struct {
-	Owner string
-	Repo  string
+	Command string
+
+	Repos           []repoArg
+	Org             string
+	IncludeForks    bool
+	IncludeArchived bool
+	PerRepo         bool
+	PerWeek         bool
+	Format          string
+
+	Columns      []string
+	LinkProfiles bool
+
+	Template       string
+	TemplateString string
+
+	OutDir string
+	Top    int
+	Sort   []app.SortKey
+
+	Spark bool
+	Bars  bool
+
 	From  time.Time
 	To    time.Time
 	All   bool
+	Where func(app.Contributor) bool
+
+	CompareFrom time.Time
+	CompareTo   time.Time
+
+	ExcludeBots   bool
+	IncludeLogins string
+	ExcludeLogins string
+	Aliases       string
+
+	GroupBy     string
+	Teams       string
+	GitHubTeams []string
+
+	Interval string
+	Metric   string
+	Coverage float64
+	Window   int
+
+	Precision string
+	Parallel  int
+
+	PollInterval time.Duration
+	PollTimeout  time.Duration
+
+	Token             string
+	AppID             int64
+	AppInstallationID int64
+	AppPrivateKey     string
+
+	WaitForRateLimit bool
+
+	APIURL     string
+	CACert     string
+	ClientCert string
+	ClientKey  string
+}
+
+// repoArg is a repository given as an argument. Name can be a pattern
+type repoArg struct {
+	Owner string
+	Name  string
+}
+
+func (r repoArg) String() string {
+	return r.Owner + "/" + r.Name
+}
+
+// IsPattern returns true if the name is a pattern rather than a single repository
+func (r repoArg) IsPattern() bool {
+	return strings.ContainsAny(r.Name, "*?[")
 }
 
 // splitting this out makes testing easier
@@ -147,13 +507,148 @@ func processInput(p rawInputs) (processedInputs, error) {
 		return processedInputs{}, errors.New("[processInput] invalid combination of date range arguments")
 	}
 
-	rs := strings.Split(p.Repo, "/")
-	if len(rs) != 2 {
-		return processedInputs{}, errors.New("[processInput] invalid argument. repo should be given in the form <owner>/<repo>")
+	precision := p.Precision
+	if precision == "" {
+		precision = precisionWeek
+	}
+	if precision != precisionWeek && precision != precisionDay {
+		return processedInputs{}, errors.New("[processInput] invalid `precision` value provided. Should be `week` or `day`")
+	}
+
+	groupBy := p.GroupBy
+	if groupBy == "" {
+		groupBy = app.GroupByContributor
+	}
+	if groupBy != app.GroupByContributor && groupBy != app.GroupByTeam {
+		return processedInputs{}, errors.New("[processInput] invalid `group-by` value provided. Should be `contributor` or `team`")
+	}
+
+	var githubTeams []string
+	if p.GitHubTeams != "" {
+		githubTeams = strings.Split(p.GitHubTeams, ",")
+		for _, t := range githubTeams {
+			if parts := strings.Split(t, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return processedInputs{}, errors.Errorf("[processInput] invalid `github-teams` value provided. %s should be given in the form <org>/<team-slug>", t)
+			}
+		}
+	}
+
+	if groupBy == app.GroupByTeam && p.Teams == "" && len(githubTeams) == 0 && p.Aliases == "" {
+		return processedInputs{}, errors.New("[processInput] --group-by team needs --teams, --github-teams or --aliases")
+	}
+
+	if p.Interval != "" && !isInterval(p.Interval) {
+		return processedInputs{}, errors.Errorf("[processInput] invalid `interval` value provided. Should be one of: %s", strings.Join(app.Intervals, ", "))
+	}
+
+	// commits are only kept per week, which would put some of them in the period before the one they're in.
+	// The trend of the analyze command uses exact date ranges instead.
+	if precision == precisionDay && p.Interval != "" && p.Interval != app.IntervalWeek && p.Command != cmdAnalyze {
+		return processedInputs{}, errors.New("[processInput] --precision day can only be used with --interval week")
+	}
+
+	metric := p.Metric
+	if metric == "" {
+		metric = app.SortCommits
+	}
+	if app.Metric(metric) == nil {
+		return processedInputs{}, errors.Errorf("[processInput] invalid `metric` value provided. Should be one of: %s", strings.Join(app.MetricNames(), ", "))
+	}
+
+	format := p.Format
+	if format == "" {
+		format = formatText
+	}
+	if !isFormat(format) {
+		return processedInputs{}, errors.Errorf("[processInput] invalid `format` value provided. Should be one of: %s", strings.Join(formats, ", "))
+	}
+	if format == formatMarkdown && (p.PerRepo || p.PerWeek) {
+		return processedInputs{}, errors.New("[processInput] --per-repo and --per-week can not be used with --format markdown")
+	}
+
+	var cols []string
+	if p.Columns != "" {
+		cols = strings.Split(p.Columns, ",")
+		if _, err := findColumns(cols); err != nil {
+			return processedInputs{}, errors.Wrap(err, "[processInput] invalid `columns` value provided")
+		}
+	}
+
+	if p.Template != "" && p.TemplateString != "" {
+		return processedInputs{}, errors.New("[processInput] --template and --template-string can not be used together")
+	}
+
+	var sortKeys []app.SortKey
+	if p.Sort != "" {
+		var err error
+		sortKeys, err = app.ParseSortKeys(p.Sort)
+		if err != nil {
+			return processedInputs{}, errors.Wrap(err, "[processInput] invalid `sort` value provided")
+		}
+	}
+
+	var where func(app.Contributor) bool
+	if p.Where != "" {
+		var err error
+		where, err = app.CompileWhere(p.Where)
+		if err != nil {
+			return processedInputs{}, errors.Wrap(err, "[processInput] invalid `where` value provided")
+		}
+	}
+
+	if p.Top < 0 {
+		return processedInputs{}, errors.New("[processInput] --top can not be negative")
+	}
+
+	if p.Parallel < 0 {
+		return processedInputs{}, errors.New("[processInput] --parallel can not be negative")
+	}
+
+	if p.PollInterval < 0 || p.PollTimeout < 0 {
+		return processedInputs{}, errors.New("[processInput] --poll-interval and --poll-timeout can not be negative")
+	}
+
+	usingApp := p.AppID != 0 || p.AppInstallationID != 0 || p.AppPrivateKey != ""
+	if usingApp && (p.AppID == 0 || p.AppInstallationID == 0 || p.AppPrivateKey == "") {
+		return processedInputs{}, errors.New("[processInput] --app-id, --app-installation-id and --app-private-key must be used together")
+	}
+
+	// a token from the environment is ignored when using an app
+	token := p.Token
+	if usingApp {
+		token = ""
+	}
+
+	if (p.ClientCert == "") != (p.ClientKey == "") {
+		return processedInputs{}, errors.New("[processInput] --client-cert and --client-key must be used together")
+	}
+
+	apiURL := githubBaseURL
+	if p.APIURL != "" {
+		u, err := url.Parse(p.APIURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return processedInputs{}, errors.New("[processInput] invalid `api-url` value provided. Format: https://[hostname]/api/v3")
+		}
+		apiURL = strings.TrimSuffix(p.APIURL, "/")
+	}
+
+	var repos []repoArg
+	for _, r := range p.Repos {
+		rs := strings.Split(r, "/")
+		if len(rs) != 2 || rs[0] == "" || rs[1] == "" {
+			return processedInputs{}, errors.New("[processInput] invalid argument. repo should be given in the form <owner>/<repo>")
+		}
+		if _, err := path.Match(rs[1], ""); err != nil || strings.ContainsAny(rs[0], "*?[") {
+			return processedInputs{}, errors.Errorf("[processInput] invalid argument. %s is not a valid pattern. Only the repo name can contain wildcards", r)
+		}
+		repos = append(repos, repoArg{Owner: rs[0], Name: rs[1]})
+	}
+
+	if len(repos) == 0 && p.Org == "" {
+		return processedInputs{}, errors.New("[processInput] repository or --org must be specified")
 	}
-	repoOwner, repoName := rs[0], rs[1]
 
-	from, to := time.Time{}, time.Now()
+	from, to := time.Time{}, now()
 
 	if p.From != "" {
 		var err error // delaration required here so that `from` on next line refers to var in parent scope
@@ -173,24 +668,239 @@ func processInput(p rawInputs) (processedInputs, error) {
 
 	// already check flag combination by this point so no need to worry about from or to
 	if p.Weeks != 0 || p.Months != 0 || p.Years != 0 {
-		from = time.Now().AddDate(-p.Years, -p.Months, -(p.Weeks * 7))
+		// from `to` rather than another now(), so that e.g. --months 3 is exactly 3 months (see app.PreviousRange)
+		from = to.AddDate(-p.Years, -p.Months, -(p.Weeks * 7))
+	}
+
+	var compareFrom, compareTo time.Time
+	if p.CompareFrom != "" || p.CompareTo != "" {
+		if p.CompareFrom == "" || p.CompareTo == "" {
+			return processedInputs{}, errors.New("[processInput] --compare-from and --compare-to must be used together")
+		}
+		var err error
+		if compareFrom, err = time.Parse("2006-01-02", p.CompareFrom); err != nil {
+			return processedInputs{}, errors.New("[processInput] invalid `compare-from` value provided. Format: YYYY-MM-DD")
+		}
+		if compareTo, err = time.Parse("2006-01-02", p.CompareTo); err != nil {
+			return processedInputs{}, errors.New("[processInput] invalid `compare-to` value provided. Format: YYYY-MM-DD")
+		}
+	}
+
+	if p.Command == cmdCompare {
+		if from.IsZero() {
+			return processedInputs{}, errors.New("[processInput] the compare command needs a date range with a lower bound: --from, --weeks, --months or --years")
+		}
+		if format == formatHTML || p.Interval != "" {
+			return processedInputs{}, errors.New("[processInput] the compare command can not be used with --format html or --interval")
+		}
+	}
+
+	coverage := p.Coverage
+	if coverage == 0 {
+		coverage = defaultCoverage
+	}
+	if coverage < 0 || coverage > 100 {
+		return processedInputs{}, errors.New("[processInput] invalid `coverage` value provided. Should be more than 0 and at most 100")
+	}
+
+	window := p.Window
+	if window == 0 {
+		window = defaultWindow
+	}
+	if window < 0 {
+		return processedInputs{}, errors.New("[processInput] invalid `window` value provided. Should be a number of weeks")
+	}
+
+	if p.Command == cmdAnalyze {
+		if metric == app.SortNet {
+			return processedInputs{}, errors.New("[processInput] the analyze command can't measure net lines, which can be negative. Use commits, additions, deletions or churn")
+		}
+		if format == formatHTML {
+			return processedInputs{}, errors.New("[processInput] the analyze command can not be used with --format html")
+		}
+		if p.Interval != "" && from.IsZero() {
+			return processedInputs{}, errors.New("[processInput] the trend of the analyze command needs a date range with a lower bound: --from, --weeks, --months or --years")
+		}
 	}
 
 	return processedInputs{
-		Owner: repoOwner,
-		Repo:  repoName,
+		Command: p.Command,
+
+		Repos:           repos,
+		Org:             p.Org,
+		IncludeForks:    p.IncludeForks,
+		IncludeArchived: p.IncludeArchived,
+		PerRepo:         p.PerRepo,
+		PerWeek:         p.PerWeek,
+		Format:          format,
+
+		Columns:      cols,
+		LinkProfiles: p.LinkProfiles,
+
+		Template:       p.Template,
+		TemplateString: p.TemplateString,
+
+		OutDir: p.OutDir,
+		Top:    p.Top,
+		Sort:   sortKeys,
+
+		Spark: p.Spark,
+		Bars:  p.Bars,
+
 		From:  from,
 		To:    to,
 		All:   p.All,
+		Where: where,
+
+		CompareFrom: compareFrom,
+		CompareTo:   compareTo,
+
+		ExcludeBots:   p.ExcludeBots,
+		IncludeLogins: p.IncludeLogins,
+		ExcludeLogins: p.ExcludeLogins,
+		Aliases:       p.Aliases,
+
+		GroupBy:     groupBy,
+		Teams:       p.Teams,
+		GitHubTeams: githubTeams,
+
+		Interval: p.Interval,
+		Metric:   metric,
+		Coverage: coverage,
+		Window:   window,
+
+		Precision: precision,
+		Parallel:  p.Parallel,
+
+		PollInterval: p.PollInterval,
+		PollTimeout:  p.PollTimeout,
+
+		Token:             token,
+		AppID:             p.AppID,
+		AppInstallationID: p.AppInstallationID,
+		AppPrivateKey:     p.AppPrivateKey,
+
+		WaitForRateLimit: p.WaitForRateLimit,
+
+		APIURL:     apiURL,
+		CACert:     p.CACert,
+		ClientCert: p.ClientCert,
+		ClientKey:  p.ClientKey,
 	}, nil
 }
 
-func printStats(items []app.Contributor) {
-	// use tabwriter because some usrenames are long
-	// reference: https://blog.robphoenix.com/go/aligning-text-in-go-with-tabwriter/
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	for _, item := range items {
-		fmt.Fprintf(w, item.String())
+// isFormat returns true if f is one of the output formats
+func isFormat(f string) bool {
+	for _, format := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// isInterval returns true if i is one of app.Intervals
+func isInterval(i string) bool {
+	for _, interval := range app.Intervals {
+		if i == interval {
+			return true
+		}
+	}
+	return false
+}
+
+// commandsUsage lists the commands and their descriptions, one per line
+func commandsUsage() string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "\t%-14s %s\n", name, commands[name])
+	}
+	return b.String()
+}
+
+// exitCode returns the exit code for the class of the given error
+func exitCode(err error) int {
+	if batchErr, ok := err.(*github.BatchError); ok {
+		return batchExitCode(batchErr)
+	}
+
+	cause := errors.Cause(err)
+	// errors from RoundTrippers (e.g. minting app tokens) get wrapped by the http client
+	if uerr, ok := cause.(*url.Error); ok {
+		cause = errors.Cause(uerr.Err)
 	}
-	w.Flush()
+
+	if cause == github.ErrStatsPending {
+		return exitStatsPending
+	}
+
+	switch e := cause.(type) {
+	case *github.RateLimitError:
+		return exitRateLimited
+	case *github.APIError:
+		switch e.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return exitUnauthorized
+		case http.StatusNotFound:
+			return exitNotFound
+		}
+		return exitGitHubError
+	}
+
+	return exitError
+}
+
+// batchExitCode returns exitPartial if only some of the repos failed.
+// Otherwise it's the exit code shared by all the failures, or exitError if they differ.
+func batchExitCode(e *github.BatchError) int {
+	if len(e.Errors) < e.Total {
+		return exitPartial
+	}
+	code := exitError
+	for i, re := range e.Errors {
+		c := exitCode(re.Err)
+		if i > 0 && c != code {
+			return exitError
+		}
+		code = c
+	}
+	return code
+}
+
+// newHTTPClient returns the http.Client used to send requests to GitHub, configured with
+// the given TLS options and credentials. Requests are anonymous if no credentials were given.
+func newHTTPClient(p processedInputs) (*http.Client, error) {
+	tr, err := github.NewTransport(github.TLSOpts{CAFile: p.CACert, CertFile: p.ClientCert, KeyFile: p.ClientKey})
+	if err != nil {
+		return nil, err
+	}
+	apiURL, err := url.Parse(p.APIURL)
+	if err != nil {
+		return nil, fmt.Errorf("[newHTTPClient] invalid api url: %s", err)
+	}
+
+	if p.AppPrivateKey != "" {
+		key, err := ioutil.ReadFile(p.AppPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("[newHTTPClient] could not read app private key: %s", err)
+		}
+		src, err := github.NewAppTokenSource(p.APIURL, p.AppID, p.AppInstallationID, key)
+		if err != nil {
+			return nil, err
+		}
+		src.Base = tr
+		return &http.Client{Transport: &github.AuthTransport{Source: src, Host: apiURL.Host, Base: tr}}, nil
+	}
+
+	if p.Token != "" {
+		return &http.Client{Transport: &github.AuthTransport{Source: github.StaticToken(p.Token), Host: apiURL.Host, Base: tr}}, nil
+	}
+
+	return &http.Client{Transport: tr}, nil
 }
@@ -2,8 +2,15 @@ package main
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+	"github.com/pkg/errors"
 )
 
 func TestProcessInput(t *testing.T) {
@@ -22,113 +29,161 @@ func TestProcessInput(t *testing.T) {
 		{
 			Name: "From and To",
 			Input: rawInputs{
-				Repo: "test-owner/test-repo",
-				From: testDateStr,
-				To:   testDateStr,
+				Repos: []string{"test-owner/test-repo"},
+				From:  testDateStr,
+				To:    testDateStr,
 			},
 			ExpectRes: processedInputs{
-				Owner: "test-owner",
-				Repo:  "test-repo",
-				From:  testDate,
-				To:    testDate,
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				From:      testDate,
+				To:        testDate,
 			},
 		},
 		{
 			Name: "just From",
 			Input: rawInputs{
-				Repo: "test-owner/test-repo",
-				From: testDateStr,
+				Repos: []string{"test-owner/test-repo"},
+				From:  testDateStr,
 			},
 			ExpectRes: processedInputs{
-				Owner: "test-owner",
-				Repo:  "test-repo",
-				From:  testDate,
-				To:    time.Now(),
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				From:      testDate,
+				To:        time.Now(),
 			},
 		},
 		{
 			Name: "just To",
 			Input: rawInputs{
-				Repo: "test-owner/test-repo",
-				To:   testDateStr,
+				Repos: []string{"test-owner/test-repo"},
+				To:    testDateStr,
 			},
 			ExpectRes: processedInputs{
-				Owner: "test-owner",
-				Repo:  "test-repo",
-				From:  time.Time{},
-				To:    testDate,
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				From:      time.Time{},
+				To:        testDate,
 			},
 		},
 		{
 			Name: "just Weeks",
 			Input: rawInputs{
-				Repo:  "test-owner/test-repo",
+				Repos: []string{"test-owner/test-repo"},
 				Weeks: 2,
 			},
 			ExpectRes: processedInputs{
-				Owner: "test-owner",
-				Repo:  "test-repo",
-				From:  time.Now().AddDate(0, 0, -14),
-				To:    time.Now(),
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				From:      time.Now().AddDate(0, 0, -14),
+				To:        time.Now(),
 			},
 		},
 		{
 			Name: "just months",
 			Input: rawInputs{
-				Repo:   "test-owner/test-repo",
+				Repos:  []string{"test-owner/test-repo"},
 				Months: 1,
 			},
 			ExpectRes: processedInputs{
-				Owner: "test-owner",
-				Repo:  "test-repo",
-				From:  time.Now().AddDate(0, -1, 0),
-				To:    time.Now(),
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				From:      time.Now().AddDate(0, -1, 0),
+				To:        time.Now(),
 			},
 		},
 		{
 			Name: "just years",
 			Input: rawInputs{
-				Repo:  "test-owner/test-repo",
+				Repos: []string{"test-owner/test-repo"},
 				Years: 3,
 			},
 			ExpectRes: processedInputs{
-				Owner: "test-owner",
-				Repo:  "test-repo",
-				From:  time.Now().AddDate(-3, 0, 0),
-				To:    time.Now(),
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				From:      time.Now().AddDate(-3, 0, 0),
+				To:        time.Now(),
 			},
 		},
 		{
 			Name: "years, months, weeks",
 			Input: rawInputs{
-				Repo:   "test-owner/test-repo",
+				Repos:  []string{"test-owner/test-repo"},
 				Years:  3,
 				Months: 2,
 				Weeks:  1,
 			},
 			ExpectRes: processedInputs{
-				Owner: "test-owner",
-				Repo:  "test-repo",
-				From:  time.Now().AddDate(-3, -2, -7),
-				To:    time.Now(),
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				From:      time.Now().AddDate(-3, -2, -7),
+				To:        time.Now(),
 			},
 		},
 		{
 			Name: "None",
 			Input: rawInputs{
-				Repo: "test-owner/test-repo",
+				Repos: []string{"test-owner/test-repo"},
 			},
 			ExpectRes: processedInputs{
-				Owner: "test-owner",
-				Repo:  "test-repo",
-				From:  time.Time{},
-				To:    time.Now(),
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				From:      time.Time{},
+				To:        time.Now(),
 			},
 		},
 		{
 			Name: "invalid combo From and Weeks",
 			Input: rawInputs{
-				Repo:  "test-owner/test-repo",
+				Repos: []string{"test-owner/test-repo"},
 				From:  testDateStr,
 				Weeks: 1,
 			},
@@ -137,7 +192,7 @@ func TestProcessInput(t *testing.T) {
 		{
 			Name: "invalid combo From and Months",
 			Input: rawInputs{
-				Repo:   "test-owner/test-repo",
+				Repos:  []string{"test-owner/test-repo"},
 				From:   testDateStr,
 				Months: 1,
 			},
@@ -146,7 +201,7 @@ func TestProcessInput(t *testing.T) {
 		{
 			Name: "invalid combo From and Years",
 			Input: rawInputs{
-				Repo:  "test-owner/test-repo",
+				Repos: []string{"test-owner/test-repo"},
 				From:  testDateStr,
 				Years: 1,
 			},
@@ -155,7 +210,7 @@ func TestProcessInput(t *testing.T) {
 		{
 			Name: "invalid combo To and Weeks",
 			Input: rawInputs{
-				Repo:  "test-owner/test-repo",
+				Repos: []string{"test-owner/test-repo"},
 				To:    testDateStr,
 				Weeks: 1,
 			},
@@ -164,7 +219,7 @@ func TestProcessInput(t *testing.T) {
 		{
 			Name: "invalid combo To and Months",
 			Input: rawInputs{
-				Repo:   "test-owner/test-repo",
+				Repos:  []string{"test-owner/test-repo"},
 				To:     testDateStr,
 				Months: 1,
 			},
@@ -173,32 +228,709 @@ func TestProcessInput(t *testing.T) {
 		{
 			Name: "invalid combo To and Years",
 			Input: rawInputs{
-				Repo:  "test-owner/test-repo",
+				Repos: []string{"test-owner/test-repo"},
 				To:    testDateStr,
 				Years: 1,
 			},
 			ExpectErr: fmt.Errorf("[processInput] invalid combination of date range arguments"),
 		},
+		{
+			Name: "negative poll interval",
+			Input: rawInputs{
+				Repos:        []string{"test-owner/test-repo"},
+				PollInterval: -time.Second,
+			},
+			ExpectErr: fmt.Errorf("[processInput] --poll-interval and --poll-timeout can not be negative"),
+		},
+		{
+			Name: "token",
+			Input: rawInputs{
+				Repos: []string{"test-owner/test-repo"},
+				Token: "test-token",
+			},
+			ExpectRes: processedInputs{
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				From:      time.Time{},
+				To:        time.Now(),
+				Token:     "test-token",
+			},
+		},
+		{
+			Name: "app ignores token",
+			Input: rawInputs{
+				Repos:             []string{"test-owner/test-repo"},
+				Token:             "test-token",
+				AppID:             1,
+				AppInstallationID: 2,
+				AppPrivateKey:     "key.pem",
+			},
+			ExpectRes: processedInputs{
+				Repos:             []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:            githubBaseURL,
+				Precision:         precisionWeek,
+				GroupBy:           app.GroupByContributor,
+				Metric:            app.SortCommits,
+				Coverage:          defaultCoverage,
+				Window:            defaultWindow,
+				Format:            formatText,
+				From:              time.Time{},
+				To:                time.Now(),
+				AppID:             1,
+				AppInstallationID: 2,
+				AppPrivateKey:     "key.pem",
+			},
+		},
+		{
+			Name: "incomplete app",
+			Input: rawInputs{
+				Repos: []string{"test-owner/test-repo"},
+				AppID: 1,
+			},
+			ExpectErr: fmt.Errorf("[processInput] --app-id, --app-installation-id and --app-private-key must be used together"),
+		},
+		{
+			Name: "enterprise",
+			Input: rawInputs{
+				Repos:      []string{"test-owner/test-repo"},
+				APIURL:     "https://ghe.corp/api/v3/",
+				CACert:     "ca.pem",
+				ClientCert: "cert.pem",
+				ClientKey:  "key.pem",
+			},
+			ExpectRes: processedInputs{
+				Repos:      []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				From:       time.Time{},
+				To:         time.Now(),
+				APIURL:     "https://ghe.corp/api/v3",
+				Precision:  precisionWeek,
+				GroupBy:    app.GroupByContributor,
+				Metric:     app.SortCommits,
+				Coverage:   defaultCoverage,
+				Window:     defaultWindow,
+				Format:     formatText,
+				CACert:     "ca.pem",
+				ClientCert: "cert.pem",
+				ClientKey:  "key.pem",
+			},
+		},
+		{
+			Name: "invalid api url",
+			Input: rawInputs{
+				Repos:  []string{"test-owner/test-repo"},
+				APIURL: "ghe.corp/api/v3",
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `api-url` value provided. Format: https://[hostname]/api/v3"),
+		},
+		{
+			Name: "client cert without key",
+			Input: rawInputs{
+				Repos:      []string{"test-owner/test-repo"},
+				ClientCert: "cert.pem",
+			},
+			ExpectErr: fmt.Errorf("[processInput] --client-cert and --client-key must be used together"),
+		},
+		{
+			Name: "day precision",
+			Input: rawInputs{
+				Repos:     []string{"test-owner/test-repo"},
+				Precision: "day",
+			},
+			ExpectRes: processedInputs{
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				From:      time.Time{},
+				To:        time.Now(),
+				APIURL:    githubBaseURL,
+				Precision: precisionDay,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+			},
+		},
+		{
+			Name: "invalid precision",
+			Input: rawInputs{
+				Repos:     []string{"test-owner/test-repo"},
+				Precision: "hour",
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `precision` value provided. Should be `week` or `day`"),
+		},
+		{
+			Name: "several repos and patterns",
+			Input: rawInputs{
+				Repos:   []string{"test-owner/test-repo", "test-owner/api-*", "other-owner/other-repo"},
+				PerRepo: true,
+			},
+			ExpectRes: processedInputs{
+				Repos: []repoArg{
+					{Owner: "test-owner", Name: "test-repo"},
+					{Owner: "test-owner", Name: "api-*"},
+					{Owner: "other-owner", Name: "other-repo"},
+				},
+				PerRepo:   true,
+				From:      time.Time{},
+				To:        time.Now(),
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+			},
+		},
+		{
+			Name: "org",
+			Input: rawInputs{
+				Org:             "test-org",
+				IncludeArchived: true,
+			},
+			ExpectRes: processedInputs{
+				Org:             "test-org",
+				IncludeArchived: true,
+				From:            time.Time{},
+				To:              time.Now(),
+				APIURL:          githubBaseURL,
+				Precision:       precisionWeek,
+				GroupBy:         app.GroupByContributor,
+				Metric:          app.SortCommits,
+				Coverage:        defaultCoverage,
+				Window:          defaultWindow,
+				Format:          formatText,
+			},
+		},
+		{
+			Name: "Parallel",
+			Input: rawInputs{
+				Repos:    []string{"test-owner/test-repo"},
+				Parallel: 8,
+			},
+			ExpectRes: processedInputs{
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				Parallel:  8,
+				To:        time.Now(),
+			},
+		},
+		{
+			Name: "json format",
+			Input: rawInputs{
+				Repos:  []string{"test-owner/test-repo"},
+				Format: formatJSON,
+			},
+			ExpectRes: processedInputs{
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatJSON,
+				To:        time.Now(),
+			},
+		},
+		{
+			Name: "tsv per week",
+			Input: rawInputs{
+				Repos:   []string{"test-owner/test-repo"},
+				Format:  formatTSV,
+				PerWeek: true,
+			},
+			ExpectRes: processedInputs{
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatTSV,
+				PerWeek:   true,
+				To:        time.Now(),
+			},
+		},
+		{
+			Name: "template",
+			Input: rawInputs{
+				Repos:    []string{"test-owner/test-repo"},
+				Template: "report.tmpl",
+			},
+			ExpectRes: processedInputs{
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				Template:  "report.tmpl",
+				To:        time.Now(),
+			},
+		},
+		{
+			Name: "markdown columns",
+			Input: rawInputs{
+				Repos:        []string{"test-owner/test-repo"},
+				Format:       formatMarkdown,
+				Columns:      "login,commits",
+				LinkProfiles: true,
+			},
+			ExpectRes: processedInputs{
+				Repos:        []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:       githubBaseURL,
+				Precision:    precisionWeek,
+				GroupBy:      app.GroupByContributor,
+				Metric:       app.SortCommits,
+				Coverage:     defaultCoverage,
+				Window:       defaultWindow,
+				Format:       formatMarkdown,
+				Columns:      []string{"login", "commits"},
+				LinkProfiles: true,
+				To:           time.Now(),
+			},
+		},
+		{
+			Name: "invalid column",
+			Input: rawInputs{
+				Repos:   []string{"test-owner/test-repo"},
+				Columns: "login,stars",
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `columns` value provided: [findColumns] unknown column \"stars\". Should be one of: login, commits, additions, deletions, net, churn, lines_per_commit, commit_share, line_share, name, team"),
+		},
+		{
+			Name: "template and template string",
+			Input: rawInputs{
+				Repos:          []string{"test-owner/test-repo"},
+				Template:       "report.tmpl",
+				TemplateString: "{{.Repos}}",
+			},
+			ExpectErr: fmt.Errorf("[processInput] --template and --template-string can not be used together"),
+		},
+		{
+			Name: "invalid format",
+			Input: rawInputs{
+				Repos:  []string{"test-owner/test-repo"},
+				Format: "xml",
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `format` value provided. Should be one of: text, json, csv, tsv, markdown, html"),
+		},
+		{
+			Name: "markdown per repo",
+			Input: rawInputs{
+				Repos:   []string{"test-owner/test-repo"},
+				Format:  formatMarkdown,
+				PerRepo: true,
+			},
+			ExpectErr: fmt.Errorf("[processInput] --per-repo and --per-week can not be used with --format markdown"),
+		},
+		{
+			Name: "chart",
+			Input: rawInputs{
+				Command: cmdChart,
+				Repos:   []string{"test-owner/test-repo"},
+				OutDir:  "docs",
+				Top:     5,
+			},
+			ExpectRes: processedInputs{
+				Command:   cmdChart,
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				OutDir:    "docs",
+				Top:       5,
+				To:        time.Now(),
+			},
+		},
+		{
+			Name: "compare",
+			Input: rawInputs{
+				Command:     cmdCompare,
+				Repos:       []string{"test-owner/test-repo"},
+				From:        "2018-07-01",
+				To:          "2018-10-01",
+				CompareFrom: "2017-07-01",
+				CompareTo:   "2017-10-01",
+			},
+			ExpectRes: processedInputs{
+				Command:     cmdCompare,
+				Repos:       []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:      githubBaseURL,
+				Precision:   precisionWeek,
+				GroupBy:     app.GroupByContributor,
+				Metric:      app.SortCommits,
+				Coverage:    defaultCoverage,
+				Window:      defaultWindow,
+				Format:      formatText,
+				From:        time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC),
+				To:          time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC),
+				CompareFrom: time.Date(2017, 7, 1, 0, 0, 0, 0, time.UTC),
+				CompareTo:   time.Date(2017, 10, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Name: "compare without a lower bound",
+			Input: rawInputs{
+				Command: cmdCompare,
+				Repos:   []string{"test-owner/test-repo"},
+			},
+			ExpectErr: fmt.Errorf("[processInput] the compare command needs a date range with a lower bound: --from, --weeks, --months or --years"),
+		},
+		{
+			Name: "compare with html",
+			Input: rawInputs{
+				Command: cmdCompare,
+				Repos:   []string{"test-owner/test-repo"},
+				Months:  3,
+				Format:  "html",
+			},
+			ExpectErr: fmt.Errorf("[processInput] the compare command can not be used with --format html or --interval"),
+		},
+		{
+			Name: "compare from without compare to",
+			Input: rawInputs{
+				Command:     cmdCompare,
+				Repos:       []string{"test-owner/test-repo"},
+				Months:      3,
+				CompareFrom: "2017-07-01",
+			},
+			ExpectErr: fmt.Errorf("[processInput] --compare-from and --compare-to must be used together"),
+		},
+		{
+			Name: "invalid compare to",
+			Input: rawInputs{
+				Command:     cmdCompare,
+				Repos:       []string{"test-owner/test-repo"},
+				Months:      3,
+				CompareFrom: "2017-07-01",
+				CompareTo:   "2017-10",
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `compare-to` value provided. Format: YYYY-MM-DD"),
+		},
+		{
+			Name: "analyze",
+			Input: rawInputs{
+				Command:  cmdAnalyze,
+				Repos:    []string{"test-owner/test-repo"},
+				From:     "2018-01-01",
+				To:       "2019-01-01",
+				Interval: "month",
+				Metric:   "churn",
+				Coverage: 80,
+				Window:   4,
+			},
+			ExpectRes: processedInputs{
+				Command:   cmdAnalyze,
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Interval:  app.IntervalMonth,
+				Metric:    app.SortChurn,
+				Coverage:  80,
+				Window:    4,
+				Format:    formatText,
+				From:      time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:        time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Name: "interval month with precision day",
+			Input: rawInputs{
+				Repos:     []string{"test-owner/test-repo"},
+				From:      "2018-10-01",
+				Precision: "day",
+				Interval:  "month",
+			},
+			ExpectErr: fmt.Errorf("[processInput] --precision day can only be used with --interval week"),
+		},
+		{
+			Name: "analyze net",
+			Input: rawInputs{
+				Command: cmdAnalyze,
+				Repos:   []string{"test-owner/test-repo"},
+				Metric:  "net",
+			},
+			ExpectErr: fmt.Errorf("[processInput] the analyze command can't measure net lines, which can be negative. Use commits, additions, deletions or churn"),
+		},
+		{
+			Name: "analyze trend without a lower bound",
+			Input: rawInputs{
+				Command:  cmdAnalyze,
+				Repos:    []string{"test-owner/test-repo"},
+				Interval: "month",
+			},
+			ExpectErr: fmt.Errorf("[processInput] the trend of the analyze command needs a date range with a lower bound: --from, --weeks, --months or --years"),
+		},
+		{
+			Name: "invalid coverage",
+			Input: rawInputs{
+				Repos:    []string{"test-owner/test-repo"},
+				Coverage: 120,
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `coverage` value provided. Should be more than 0 and at most 100"),
+		},
+		{
+			Name: "spark and bars",
+			Input: rawInputs{
+				Repos: []string{"test-owner/test-repo"},
+				Spark: true,
+				Bars:  true,
+			},
+			ExpectRes: processedInputs{
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				Spark:     true,
+				Bars:      true,
+				To:        time.Now(),
+			},
+		},
+		{
+			Name: "sort and top",
+			Input: rawInputs{
+				Repos: []string{"test-owner/test-repo"},
+				Sort:  "-commits,name",
+				Top:   10,
+			},
+			ExpectRes: processedInputs{
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				Sort:      []app.SortKey{{Field: app.SortCommits, Desc: true}, {Field: app.SortName}},
+				Top:       10,
+				To:        time.Now(),
+			},
+		},
+		{
+			Name: "invalid sort",
+			Input: rawInputs{
+				Repos: []string{"test-owner/test-repo"},
+				Sort:  "stars",
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `sort` value provided: [ParseSortKeys] unknown sort field \"stars\". Should be one of: commits, additions, deletions, net, churn, lines_per_commit, commit_share, line_share, name"),
+		},
+		{
+			Name: "where",
+			Input: rawInputs{
+				Repos: []string{"test-owner/test-repo"},
+				Where: `commits >= 5 && login !~ "bot]$"`,
+			},
+			ExpectRes: processedInputs{
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Metric:    app.SortCommits,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				Where:     func(app.Contributor) bool { return true },
+				To:        time.Now(),
+			},
+		},
+		{
+			Name: "invalid where",
+			Input: rawInputs{
+				Repos: []string{"test-owner/test-repo"},
+				Where: "commits >> 5",
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `where` value provided: [CompileWhere] invalid expression: column 10: expected a number after commits > but found \">\""),
+		},
+		{
+			Name: "exclusions and aliases",
+			Input: rawInputs{
+				Repos:         []string{"test-owner/test-repo"},
+				ExcludeBots:   true,
+				IncludeLogins: "team.txt",
+				ExcludeLogins: "service-accounts.txt",
+				Aliases:       "people.yml",
+			},
+			ExpectRes: processedInputs{
+				Repos:         []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:        githubBaseURL,
+				Precision:     precisionWeek,
+				GroupBy:       app.GroupByContributor,
+				Metric:        app.SortCommits,
+				Coverage:      defaultCoverage,
+				Window:        defaultWindow,
+				Format:        formatText,
+				ExcludeBots:   true,
+				IncludeLogins: "team.txt",
+				ExcludeLogins: "service-accounts.txt",
+				Aliases:       "people.yml",
+				To:            time.Now(),
+			},
+		},
+		{
+			Name: "group by team",
+			Input: rawInputs{
+				Repos:       []string{"test-owner/test-repo"},
+				GroupBy:     "team",
+				Teams:       "teams.yml",
+				GitHubTeams: "test-org/backend,test-org/frontend",
+			},
+			ExpectRes: processedInputs{
+				Repos:       []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:      githubBaseURL,
+				Precision:   precisionWeek,
+				GroupBy:     app.GroupByTeam,
+				Metric:      app.SortCommits,
+				Coverage:    defaultCoverage,
+				Window:      defaultWindow,
+				Teams:       "teams.yml",
+				GitHubTeams: []string{"test-org/backend", "test-org/frontend"},
+				Format:      formatText,
+				To:          time.Now(),
+			},
+		},
+		{
+			Name: "invalid group by",
+			Input: rawInputs{
+				Repos:   []string{"test-owner/test-repo"},
+				GroupBy: "repo",
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `group-by` value provided. Should be `contributor` or `team`"),
+		},
+		{
+			Name: "group by team without teams",
+			Input: rawInputs{
+				Repos:   []string{"test-owner/test-repo"},
+				GroupBy: "team",
+			},
+			ExpectErr: fmt.Errorf("[processInput] --group-by team needs --teams, --github-teams or --aliases"),
+		},
+		{
+			Name: "invalid github teams",
+			Input: rawInputs{
+				Repos:       []string{"test-owner/test-repo"},
+				GroupBy:     "team",
+				GitHubTeams: "test-org/backend,frontend",
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `github-teams` value provided. frontend should be given in the form <org>/<team-slug>"),
+		},
+		{
+			Name: "interval",
+			Input: rawInputs{
+				Repos:    []string{"test-owner/test-repo"},
+				Interval: "month",
+				Metric:   "churn",
+			},
+			ExpectRes: processedInputs{
+				Repos:     []repoArg{{Owner: "test-owner", Name: "test-repo"}},
+				APIURL:    githubBaseURL,
+				Precision: precisionWeek,
+				GroupBy:   app.GroupByContributor,
+				Interval:  app.IntervalMonth,
+				Metric:    app.SortChurn,
+				Coverage:  defaultCoverage,
+				Window:    defaultWindow,
+				Format:    formatText,
+				To:        time.Now(),
+			},
+		},
+		{
+			Name: "invalid interval",
+			Input: rawInputs{
+				Repos:    []string{"test-owner/test-repo"},
+				Interval: "fortnight",
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `interval` value provided. Should be one of: week, month, quarter, year"),
+		},
+		{
+			Name: "invalid metric",
+			Input: rawInputs{
+				Repos:    []string{"test-owner/test-repo"},
+				Interval: "week",
+				Metric:   "name",
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid `metric` value provided. Should be one of: commits, additions, deletions, net, churn"),
+		},
+		{
+			Name: "negative Top",
+			Input: rawInputs{
+				Repos: []string{"test-owner/test-repo"},
+				Top:   -1,
+			},
+			ExpectErr: fmt.Errorf("[processInput] --top can not be negative"),
+		},
+		{
+			Name: "negative Parallel",
+			Input: rawInputs{
+				Repos:    []string{"test-owner/test-repo"},
+				Parallel: -1,
+			},
+			ExpectErr: fmt.Errorf("[processInput] --parallel can not be negative"),
+		},
+		{
+			Name:      "no repos",
+			Input:     rawInputs{},
+			ExpectErr: fmt.Errorf("[processInput] repository or --org must be specified"),
+		},
+		{
+			Name: "invalid pattern",
+			Input: rawInputs{
+				Repos: []string{"test-owner/[api"},
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid argument. test-owner/[api is not a valid pattern. Only the repo name can contain wildcards"),
+		},
+		{
+			Name: "pattern in owner",
+			Input: rawInputs{
+				Repos: []string{"test-*/test-repo"},
+			},
+			ExpectErr: fmt.Errorf("[processInput] invalid argument. test-*/test-repo is not a valid pattern. Only the repo name can contain wildcards"),
+		},
 		{
 			Name: "invalid repo",
 			Input: rawInputs{
-				Repo: "test-repo",
+				Repos: []string{"test-repo"},
 			},
 			ExpectErr: fmt.Errorf("[processInput] invalid argument. repo should be given in the form <owner>/<repo>"),
 		},
 		{
 			Name: "invalid From",
 			Input: rawInputs{
-				Repo: "test-owner/test-repo",
-				From: "blam",
+				Repos: []string{"test-owner/test-repo"},
+				From:  "blam",
 			},
 			ExpectErr: fmt.Errorf("[processInput] invalid `from` value provided. Format: YYYY-MM-DD"),
 		},
 		{
 			Name: "invalid To",
 			Input: rawInputs{
-				Repo: "test-owner/test-repo",
-				To:   "blam",
+				Repos: []string{"test-owner/test-repo"},
+				To:    "blam",
 			},
 			ExpectErr: fmt.Errorf("[processInput] invalid `to` value provided. Format: YYYY-MM-DD"),
 		},
@@ -221,12 +953,12 @@ func TestProcessInput(t *testing.T) {
 				}
 			}
 
-			if res.Owner != tc.ExpectRes.Owner {
-				t.Fatalf("processInput: Have `Owner`: %s want:%s", res.Owner, tc.ExpectRes.Owner)
+			if !reflect.DeepEqual(res.Repos, tc.ExpectRes.Repos) {
+				t.Fatalf("processInput: Have `Repos`: %v want:%v", res.Repos, tc.ExpectRes.Repos)
 			}
 
-			if res.Repo != tc.ExpectRes.Repo {
-				t.Fatalf("processInput: Have `Repo`: %s want:%s", res.Repo, tc.ExpectRes.Repo)
+			if res.Org != tc.ExpectRes.Org || res.PerRepo != tc.ExpectRes.PerRepo || res.PerWeek != tc.ExpectRes.PerWeek {
+				t.Fatalf("processInput: Have `Org`, `PerRepo`, `PerWeek`: %s, %t, %t want: %s, %t, %t", res.Org, res.PerRepo, res.PerWeek, tc.ExpectRes.Org, tc.ExpectRes.PerRepo, tc.ExpectRes.PerWeek)
 			}
 
 			if res.From.Format("2006-01-02") != tc.ExpectRes.From.Format("2006-01-02") {
@@ -237,9 +969,166 @@ func TestProcessInput(t *testing.T) {
 				t.Fatalf("processInput: Have `To`: %s want:%s", res.To.Format("2006-01-02"), tc.ExpectRes.To.Format("2006-01-02"))
 			}
 
+			if res.PollInterval != tc.ExpectRes.PollInterval || res.PollTimeout != tc.ExpectRes.PollTimeout {
+				t.Fatalf("processInput: Have `PollInterval`, `PollTimeout`: %s, %s want: %s, %s", res.PollInterval, res.PollTimeout, tc.ExpectRes.PollInterval, tc.ExpectRes.PollTimeout)
+			}
+
+			if res.Token != tc.ExpectRes.Token {
+				t.Fatalf("processInput: Have `Token`: %s want:%s", res.Token, tc.ExpectRes.Token)
+			}
+
+			if res.AppID != tc.ExpectRes.AppID || res.AppInstallationID != tc.ExpectRes.AppInstallationID || res.AppPrivateKey != tc.ExpectRes.AppPrivateKey {
+				t.Fatalf("processInput: Have app: %d, %d, %s want: %d, %d, %s", res.AppID, res.AppInstallationID, res.AppPrivateKey, tc.ExpectRes.AppID, tc.ExpectRes.AppInstallationID, tc.ExpectRes.AppPrivateKey)
+			}
+
+			if !reflect.DeepEqual(res.Columns, tc.ExpectRes.Columns) || res.LinkProfiles != tc.ExpectRes.LinkProfiles {
+				t.Fatalf("processInput: Have `Columns`, `LinkProfiles`: %v, %t want: %v, %t", res.Columns, res.LinkProfiles, tc.ExpectRes.Columns, tc.ExpectRes.LinkProfiles)
+			}
+
+			if res.Template != tc.ExpectRes.Template || res.TemplateString != tc.ExpectRes.TemplateString {
+				t.Fatalf("processInput: Have `Template`, `TemplateString`: %s, %s want: %s, %s", res.Template, res.TemplateString, tc.ExpectRes.Template, tc.ExpectRes.TemplateString)
+			}
+
+			if res.Format != tc.ExpectRes.Format {
+				t.Fatalf("processInput: Have `Format`: %s want:%s", res.Format, tc.ExpectRes.Format)
+			}
+
+			if res.Command != tc.ExpectRes.Command || res.OutDir != tc.ExpectRes.OutDir || res.Top != tc.ExpectRes.Top {
+				t.Fatalf("processInput: Have `Command`, `OutDir`, `Top`: %s, %s, %d want: %s, %s, %d", res.Command, res.OutDir, res.Top, tc.ExpectRes.Command, tc.ExpectRes.OutDir, tc.ExpectRes.Top)
+			}
+
+			if !reflect.DeepEqual(res.Sort, tc.ExpectRes.Sort) {
+				t.Fatalf("processInput: Have `Sort`: %v want:%v", res.Sort, tc.ExpectRes.Sort)
+			}
+
+			if res.Spark != tc.ExpectRes.Spark || res.Bars != tc.ExpectRes.Bars {
+				t.Fatalf("processInput: Have `Spark`, `Bars`: %t, %t want: %t, %t", res.Spark, res.Bars, tc.ExpectRes.Spark, tc.ExpectRes.Bars)
+			}
+
+			if res.Parallel != tc.ExpectRes.Parallel {
+				t.Fatalf("processInput: Have `Parallel`: %d want:%d", res.Parallel, tc.ExpectRes.Parallel)
+			}
+
+			if res.Precision != tc.ExpectRes.Precision {
+				t.Fatalf("processInput: Have `Precision`: %s want:%s", res.Precision, tc.ExpectRes.Precision)
+			}
+
+			if res.APIURL != tc.ExpectRes.APIURL {
+				t.Fatalf("processInput: Have `APIURL`: %s want:%s", res.APIURL, tc.ExpectRes.APIURL)
+			}
+
+			if res.CACert != tc.ExpectRes.CACert || res.ClientCert != tc.ExpectRes.ClientCert || res.ClientKey != tc.ExpectRes.ClientKey {
+				t.Fatalf("processInput: Have TLS: %s, %s, %s want: %s, %s, %s", res.CACert, res.ClientCert, res.ClientKey, tc.ExpectRes.CACert, tc.ExpectRes.ClientCert, tc.ExpectRes.ClientKey)
+			}
+
 			if res.All != tc.ExpectRes.All {
 				t.Fatalf("processInput: Have `All`: %t want:%t", res.All, tc.ExpectRes.All)
 			}
+
+			if (res.Where == nil) != (tc.ExpectRes.Where == nil) {
+				t.Fatalf("processInput: Have `Where` set: %t want:%t", res.Where != nil, tc.ExpectRes.Where != nil)
+			}
+
+			if res.ExcludeBots != tc.ExpectRes.ExcludeBots || res.IncludeLogins != tc.ExpectRes.IncludeLogins || res.ExcludeLogins != tc.ExpectRes.ExcludeLogins {
+				t.Fatalf("processInput: Have `ExcludeBots`, `IncludeLogins`, `ExcludeLogins`: %t, %s, %s want: %t, %s, %s", res.ExcludeBots, res.IncludeLogins, res.ExcludeLogins, tc.ExpectRes.ExcludeBots, tc.ExpectRes.IncludeLogins, tc.ExpectRes.ExcludeLogins)
+			}
+
+			if res.Aliases != tc.ExpectRes.Aliases {
+				t.Fatalf("processInput: Have `Aliases`: %s want:%s", res.Aliases, tc.ExpectRes.Aliases)
+			}
+
+			if res.GroupBy != tc.ExpectRes.GroupBy || res.Teams != tc.ExpectRes.Teams || !reflect.DeepEqual(res.GitHubTeams, tc.ExpectRes.GitHubTeams) {
+				t.Fatalf("processInput: Have `GroupBy`, `Teams`, `GitHubTeams`: %s, %s, %v want: %s, %s, %v", res.GroupBy, res.Teams, res.GitHubTeams, tc.ExpectRes.GroupBy, tc.ExpectRes.Teams, tc.ExpectRes.GitHubTeams)
+			}
+
+			if res.Interval != tc.ExpectRes.Interval || res.Metric != tc.ExpectRes.Metric {
+				t.Fatalf("processInput: Have `Interval`, `Metric`: %s, %s want: %s, %s", res.Interval, res.Metric, tc.ExpectRes.Interval, tc.ExpectRes.Metric)
+			}
+
+			if !res.CompareFrom.Equal(tc.ExpectRes.CompareFrom) || !res.CompareTo.Equal(tc.ExpectRes.CompareTo) {
+				t.Fatalf("processInput: Have `CompareFrom`, `CompareTo`: %s, %s want: %s, %s", res.CompareFrom, res.CompareTo, tc.ExpectRes.CompareFrom, tc.ExpectRes.CompareTo)
+			}
+
+			if res.IncludeForks != tc.ExpectRes.IncludeForks || res.IncludeArchived != tc.ExpectRes.IncludeArchived {
+				t.Fatalf("processInput: Have `IncludeForks`, `IncludeArchived`: %t, %t want: %t, %t", res.IncludeForks, res.IncludeArchived, tc.ExpectRes.IncludeForks, tc.ExpectRes.IncludeArchived)
+			}
+
+			if res.Coverage != tc.ExpectRes.Coverage || res.Window != tc.ExpectRes.Window {
+				t.Fatalf("processInput: Have `Coverage`, `Window`: %v, %d want: %v, %d", res.Coverage, res.Window, tc.ExpectRes.Coverage, tc.ExpectRes.Window)
+			}
+		})
+	}
+}
+
+func TestProcessInputPreviousRange(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return time.Date(2018, 6, 15, 10, 30, 0, 0, time.UTC) }
+
+	res, err := processInput(rawInputs{Command: cmdCompare, Repos: []string{"test-owner/test-repo"}, Months: 3})
+	if err != nil {
+		t.Fatalf("processInput: Unexpected Error: %v", err)
+	}
+
+	if want := time.Date(2018, 3, 15, 10, 30, 0, 0, time.UTC); !res.From.Equal(want) {
+		t.Fatalf("processInput: have `From` %s want %s", res.From, want)
+	}
+
+	// --months 3 is a whole number of months, so the previous range is the 3 months before it
+	previous := app.PreviousRange(app.CalcContrbutionsOpts{From: res.From, To: res.To})
+	want := app.CalcContrbutionsOpts{From: time.Date(2017, 12, 15, 10, 30, 0, 0, time.UTC), To: res.From}
+	if !previous.From.Equal(want.From) || !previous.To.Equal(want.To) {
+		t.Errorf("PreviousRange: have %s to %s want %s to %s", previous.From, previous.To, want.From, want.To)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	ts := []struct {
+		Name   string
+		Err    error
+		Expect int
+	}{
+		{Name: "Generic", Err: fmt.Errorf("boom"), Expect: exitError},
+		{Name: "Stats Pending", Err: errors.Wrap(github.ErrStatsPending, "[ListContributorStats]"), Expect: exitStatsPending},
+		{Name: "Unauthorized", Err: errors.Wrap(&github.APIError{StatusCode: http.StatusUnauthorized}, "[ListContributorStats]"), Expect: exitUnauthorized},
+		{Name: "Forbidden", Err: errors.Wrap(&github.APIError{StatusCode: http.StatusForbidden}, "[ListContributorStats]"), Expect: exitUnauthorized},
+		{Name: "Not Found", Err: errors.Wrap(&github.APIError{StatusCode: http.StatusNotFound}, "[ListContributorStats]"), Expect: exitNotFound},
+		{Name: "Rate Limited", Err: errors.Wrap(&github.RateLimitError{APIError: github.APIError{StatusCode: http.StatusForbidden}}, "[ListContributorStats]"), Expect: exitRateLimited},
+		{Name: "Server Error", Err: errors.Wrap(&github.APIError{StatusCode: http.StatusBadGateway}, "[ListContributorStats]"), Expect: exitGitHubError},
+		{
+			Name:   "From RoundTripper",
+			Err:    errors.Wrap(&url.Error{Op: "Get", URL: "/", Err: errors.Wrap(&github.APIError{StatusCode: http.StatusUnauthorized}, "[AppTokenSource]")}, "[ListContributorStats]"),
+			Expect: exitUnauthorized,
+		},
+		{
+			Name: "Some Repos Failed",
+			Err: &github.BatchError{Total: 3, Errors: []github.RepoError{
+				{Repo: "owner/a", Err: errors.Wrap(&github.APIError{StatusCode: http.StatusNotFound}, "[ListContributorStats]")},
+			}},
+			Expect: exitPartial,
+		},
+		{
+			Name: "All Repos Failed",
+			Err: &github.BatchError{Total: 2, Errors: []github.RepoError{
+				{Repo: "owner/a", Err: errors.Wrap(&github.APIError{StatusCode: http.StatusNotFound}, "[ListContributorStats]")},
+				{Repo: "owner/b", Err: errors.Wrap(&github.APIError{StatusCode: http.StatusNotFound}, "[ListContributorStats]")},
+			}},
+			Expect: exitNotFound,
+		},
+		{
+			Name: "All Repos Failed Differently",
+			Err: &github.BatchError{Total: 2, Errors: []github.RepoError{
+				{Repo: "owner/a", Err: errors.Wrap(&github.APIError{StatusCode: http.StatusNotFound}, "[ListContributorStats]")},
+				{Repo: "owner/b", Err: errors.Wrap(github.ErrStatsPending, "[ListContributorStats]")},
+			}},
+			Expect: exitError,
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			if res := exitCode(tc.Err); res != tc.Expect {
+				t.Errorf("exitCode: Have %d want %d", res, tc.Expect)
+			}
 		})
 	}
 }
@@ -247,5 +1136,3 @@ func TestProcessInput(t *testing.T) {
 // TODO: parseInput is a pain to test because of errors about parsing flags twice.
 // Will omit parseInput tests for now but in future should rewrite it to use a more
 // GNU-like command line parser which might not have the same testing issues
-
-// TODO: printStats tests (omitted in the interest of time).
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+// matrix is a contributor × period table of one metric, for --interval
+// - Title: describes the metric and interval e.g. "Commits per month"
+// - Corner: the title of the column of names, "Contributor" or "Team"
+// - Periods: the label of each period
+type matrix struct {
+	Title   string
+	Corner  string
+	Periods []string
+	Rows    []matrixRow
+}
+
+// matrixRow is a contributor's value of the metric in each period, and over the whole range
+type matrixRow struct {
+	Name   string
+	Values []int
+	Total  int
+}
+
+// newMatrix returns the matrix of the metric (see app.Metric) per period of the report's interval
+func newMatrix(r app.Report, metric string) matrix {
+	value := app.Metric(metric)
+	res := matrix{
+		Title:  fmt.Sprintf("%s per %s", capitalise(metric), r.Interval),
+		Corner: "Contributor",
+	}
+	if r.GroupBy == app.GroupByTeam {
+		res.Corner = "Team"
+	}
+	for _, p := range r.Periods(r.Interval) {
+		res.Periods = append(res.Periods, app.PeriodLabel(r.Interval, p))
+	}
+
+	for i, s := range r.Series(r.Interval) {
+		row := matrixRow{Name: s.Name, Values: make([]int, len(s.Stats)), Total: value(r.Contributors[i].Stats)}
+		for j, st := range s.Stats {
+			row.Values[j] = value(st)
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	return res
+}
+
+func capitalise(s string) string {
+	if s == "" {
+		return s
+	}
+	return string(unicode.ToUpper(rune(s[0]))) + s[1:]
+}
+
+// printMatrix writes the matrix as a text table, under its title
+func printMatrix(out io.Writer, m matrix) {
+	fmt.Fprintf(out, "%s:\n", m.Title)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(m.header(), "\t"))
+	for _, row := range m.Rows {
+		fmt.Fprintln(w, strings.Join(row.cells(), "\t"))
+	}
+	w.Flush()
+}
+
+// printMatrixCSV writes the matrix as CSV (or TSV) with a header row of the periods
+func printMatrixCSV(out io.Writer, m matrix, comma rune) error {
+	w := csv.NewWriter(out)
+	w.Comma = comma
+
+	header := m.header()
+	header[0] = strings.ToLower(header[0])
+	header[len(header)-1] = strings.ToLower(header[len(header)-1])
+	w.Write(header)
+	for _, row := range m.Rows {
+		w.Write(row.cells())
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// printMatrixMarkdown writes the matrix as a GitHub flavoured Markdown table, with a title line
+func printMatrixMarkdown(out io.Writer, r app.Report, m matrix) {
+	fmt.Fprintf(out, "### %s of contributors to %s %s\n\n", m.Title, strings.Join(r.Repos, ", "), describeRange(r.From, r.To))
+
+	fmt.Fprintf(out, "| %s |\n| ---%s |\n", strings.Join(m.header(), " | "), strings.Repeat(" | --:", len(m.Periods)+1))
+	for _, row := range m.Rows {
+		cells := row.cells()
+		cells[0] = escapeMarkdown(cells[0])
+		fmt.Fprintf(out, "| %s |\n", strings.Join(cells, " | "))
+	}
+}
+
+// header returns the titles of the columns: the names, each period and the total
+func (m matrix) header() []string {
+	return append(append([]string{m.Corner}, m.Periods...), "Total")
+}
+
+// cells returns the name, the value in each period and the total
+func (row matrixRow) cells() []string {
+	res := []string{row.Name}
+	for _, v := range row.Values {
+		res = append(res, strconv.Itoa(v))
+	}
+	return append(res, strconv.Itoa(row.Total))
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestPrintMatrix(t *testing.T) {
+	r := testReport
+	r.Interval = app.IntervalWeek
+
+	ts := []struct {
+		Name   string
+		Format string
+		Metric string
+		Expect string
+	}{
+		{
+			Name:   "Text",
+			Format: formatText,
+			Metric: app.SortCommits,
+			Expect: `Commits per week:
+Contributor    2018-06-03  2018-06-10  2018-06-17  2018-06-24  Total
+test-user-1    1           0           2           0           3
+Knope, Leslie  0           0           0           1           1
+`,
+		},
+		{
+			Name:   "CSV",
+			Format: formatCSV,
+			Metric: app.SortChurn,
+			Expect: `contributor,2018-06-03,2018-06-10,2018-06-17,2018-06-24,total
+test-user-1,65,0,85,0,150
+"Knope, Leslie",0,0,0,5,5
+`,
+		},
+		{
+			Name:   "Markdown",
+			Format: formatMarkdown,
+			Metric: app.SortCommits,
+			Expect: `### Commits per week of contributors to test-owner/test-repo, test-owner/other-repo from 2018-06-03 to 2018-07-01
+
+| Contributor | 2018-06-03 | 2018-06-10 | 2018-06-17 | 2018-06-24 | Total |
+| --- | --: | --: | --: | --: | --: |
+| test-user-1 | 1 | 0 | 2 | 0 | 3 |
+| Knope, Leslie | 0 | 0 | 0 | 1 | 1 |
+`,
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := printReport(&buf, r, processedInputs{Format: tc.Format, Metric: tc.Metric}); err != nil {
+				t.Fatalf("printReport: Unexpected Error: %v", err)
+			}
+			if buf.String() != tc.Expect {
+				t.Errorf("printReport:\n\nhave result:\n%s\n\nwant result:\n%s", buf.String(), tc.Expect)
+			}
+		})
+	}
+}
+
+func TestPrintMatrixTeams(t *testing.T) {
+	r := testReport
+	r.Interval = app.IntervalMonth
+	r.GroupBy = app.GroupByTeam
+
+	var buf bytes.Buffer
+	printMatrix(&buf, newMatrix(r, app.SortAdditions))
+	expect := `Additions per month:
+Team           2018-06  Total
+test-user-1    120      120
+Knope, Leslie  5        5
+`
+	if buf.String() != expect {
+		t.Errorf("printMatrix:\n\nhave result:\n%s\n\nwant result:\n%s", buf.String(), expect)
+	}
+}
+
+func TestPrintJSONPeriods(t *testing.T) {
+	r := testReport
+	r.Interval = app.IntervalMonth
+
+	var buf bytes.Buffer
+	if err := printJSON(&buf, r); err != nil {
+		t.Fatalf("printJSON: Unexpected Error: %v", err)
+	}
+
+	var res jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("printJSON: Invalid JSON: %v", err)
+	}
+	if res.Interval != app.IntervalMonth {
+		t.Errorf("printJSON: Have `interval`: %q want: %q", res.Interval, app.IntervalMonth)
+	}
+	periods := res.Contributors[0].Periods
+	if len(periods) != 1 || periods[0].Period != "2018-06" || periods[0].Commits != 3 || periods[0].Additions != 120 || periods[0].Deletions != 30 {
+		t.Errorf("printJSON: Have `periods`: %+v want a single period 2018-06 with the contributor's totals", periods)
+	}
+}
+
+func TestPrintHTMLMatrix(t *testing.T) {
+	r := testReport
+	r.Interval = app.IntervalWeek
+
+	var buf bytes.Buffer
+	if err := printHTML(&buf, r, app.SortDeletions); err != nil {
+		t.Fatalf("printHTML: Unexpected Error: %v", err)
+	}
+	for _, want := range []string{
+		"<h2>Deletions per week</h2>",
+		`<th class="num">2018-06-10</th>`,
+		`<tr><td>test-user-1</td><td class="num">5</td><td class="num">0</td><td class="num">25</td><td class="num">0</td><td class="num">30</td></tr>`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("printHTML: output doesn't contain %q", want)
+		}
+	}
+}
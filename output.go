@@ -0,0 +1,532 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/pkg/errors"
+)
+
+// Output formats of the contributors command
+const (
+	formatText     = "text"
+	formatJSON     = "json"
+	formatCSV      = "csv"
+	formatTSV      = "tsv"
+	formatMarkdown = "markdown"
+	formatHTML     = "html"
+)
+
+// formats lists the output formats in the order shown in the usage
+var formats = []string{formatText, formatJSON, formatCSV, formatTSV, formatMarkdown, formatHTML}
+
+// column is a column of the table formats
+// Optional columns are only shown when they're chosen with --columns.
+type column struct {
+	Name     string
+	Title    string
+	Numeric  bool
+	Optional bool
+	Value    func(c app.Contributor) string
+}
+
+// columns lists the columns that can be chosen with --columns, in their default order
+var columns = []column{
+	{Name: "login", Title: "Contributor", Value: func(c app.Contributor) string { return c.Name }},
+	{Name: "commits", Title: "Commits", Numeric: true, Value: func(c app.Contributor) string { return strconv.Itoa(c.Stats.Commits) }},
+	{Name: "additions", Title: "Additions", Numeric: true, Value: func(c app.Contributor) string { return strconv.Itoa(c.Stats.Additions) }},
+	{Name: "deletions", Title: "Deletions", Numeric: true, Value: func(c app.Contributor) string { return strconv.Itoa(c.Stats.Deletions) }},
+	{Name: "net", Title: "Net", Numeric: true, Optional: true, Value: func(c app.Contributor) string { return strconv.Itoa(c.Stats.Net()) }},
+	{Name: "churn", Title: "Churn", Numeric: true, Optional: true, Value: func(c app.Contributor) string { return strconv.Itoa(c.Stats.Churn()) }},
+	{Name: "lines_per_commit", Title: "Lines per commit", Numeric: true, Optional: true, Value: func(c app.Contributor) string { return formatDecimal(c.Stats.LinesPerCommit()) }},
+	{Name: "commit_share", Title: "Commit share", Numeric: true, Optional: true, Value: func(c app.Contributor) string { return formatDecimal(c.CommitShare()) + "%" }},
+	{Name: "line_share", Title: "Line share", Numeric: true, Optional: true, Value: func(c app.Contributor) string { return formatDecimal(c.LineShare()) + "%" }},
+	{Name: "name", Title: "Name", Optional: true, Value: func(c app.Contributor) string { return c.DisplayName }},
+	{Name: "team", Title: "Team", Optional: true, Value: func(c app.Contributor) string { return c.Team }},
+}
+
+// formatDecimal formats the derived metrics, which aren't whole numbers, with one decimal place e.g. "12.5"
+func formatDecimal(f float64) string {
+	return strconv.FormatFloat(f, 'f', 1, 64)
+}
+
+// columnNames returns the names of all the columns
+func columnNames() []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// defaultColumnNames returns the names of the columns shown when none are chosen
+func defaultColumnNames() []string {
+	var names []string
+	for _, col := range columns {
+		if !col.Optional {
+			names = append(names, col.Name)
+		}
+	}
+	return names
+}
+
+// findColumns returns the columns with the given names, in the given order
+func findColumns(names []string) ([]column, error) {
+	res := make([]column, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, col := range columns {
+			if col.Name == name {
+				res = append(res, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("[findColumns] unknown column %q. Should be one of: %s", name, strings.Join(columnNames(), ", "))
+		}
+	}
+	return res, nil
+}
+
+// printReport writes the report in the format given by inputs
+// With an interval, every format but JSON and HTML writes just the contributor × period matrix of inputs.Metric.
+func printReport(out io.Writer, r app.Report, inputs processedInputs) error {
+	if r.Interval != "" {
+		switch inputs.Format {
+		case formatCSV:
+			return printMatrixCSV(out, newMatrix(r, inputs.Metric), ',')
+		case formatTSV:
+			return printMatrixCSV(out, newMatrix(r, inputs.Metric), '\t')
+		case formatMarkdown:
+			printMatrixMarkdown(out, r, newMatrix(r, inputs.Metric))
+			return nil
+		case formatText:
+			printMatrix(out, newMatrix(r, inputs.Metric))
+			return nil
+		}
+	}
+
+	switch inputs.Format {
+	case formatJSON:
+		return printJSON(out, r)
+	case formatCSV:
+		return printCSV(out, r, ',', inputs.PerRepo, inputs.PerWeek)
+	case formatTSV:
+		return printCSV(out, r, '\t', inputs.PerRepo, inputs.PerWeek)
+	case formatMarkdown:
+		profileURL := ""
+		if inputs.LinkProfiles {
+			profileURL = webURL(inputs.APIURL)
+		}
+		return printMarkdown(out, r, inputs.Columns, profileURL)
+	case formatHTML:
+		return printHTML(out, r, inputs.Metric)
+	default:
+		opts := textOpts{PerRepo: inputs.PerRepo, Spark: inputs.Spark, Bars: inputs.Bars}
+		if len(inputs.Columns) > 0 {
+			var err error
+			if opts.Columns, err = findColumns(inputs.Columns); err != nil {
+				return err
+			}
+		}
+		if opts.Spark || opts.Bars {
+			opts.Width = terminalWidth()
+		}
+		printStats(out, r, opts)
+		return nil
+	}
+}
+
+// textOpts contains the options of the text format
+// - PerRepo: add a line per repo under each contributor
+// - Spark: add a sparkline of each contributor's commits per week
+// - Bars: add a bar of each contributor's commits, filling the rest of Width
+// - Width: the width of the terminal, used to fit the sparklines and bars. Zero means no limit.
+// - Columns: the columns of each contributor's line, instead of their commits, additions and deletions
+type textOpts struct {
+	PerRepo bool
+	Spark   bool
+	Bars    bool
+	Width   int
+	Columns []column
+}
+
+// minBarWidth is the width of the longest bar when the terminal is too narrow to fit the rest of the line
+const minBarWidth = 10
+
+func printStats(out io.Writer, r app.Report, opts textOpts) {
+	// the commits of each line, for the bars
+	var commits []int
+	var weeks []time.Time
+	if opts.Spark {
+		weeks = r.Weeks()
+	}
+
+	// use tabwriter because some usrenames are long
+	// reference: https://blog.robphoenix.com/go/aligning-text-in-go-with-tabwriter/
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	for _, item := range r.Contributors {
+		line := strings.TrimSuffix(item.String(), "\n")
+		if r.GroupBy == app.GroupByTeam {
+			line = fmt.Sprintf("Team: %s\t %s", item.Name, item.Stats)
+		}
+		if len(opts.Columns) > 0 {
+			line = columnsLine(item, opts.Columns, r.GroupBy == app.GroupByTeam)
+		}
+		if opts.Spark {
+			line += " " + sparkline(weeklyCommitCounts(item, weeks), opts.Width/3) + "\t"
+		}
+		fmt.Fprintln(w, line)
+		commits = append(commits, item.Stats.Commits)
+
+		for _, m := range item.Members {
+			fmt.Fprintf(w, "  Member: %s\t %s\n", m.Label(), m.Stats)
+			commits = append(commits, m.Stats.Commits)
+		}
+
+		if opts.PerRepo {
+			for _, r := range item.Repos {
+				fmt.Fprintf(w, "  Repo: %s\t %s\n", r.Repo, r.Stats)
+				commits = append(commits, r.Stats.Commits)
+			}
+		}
+	}
+	w.Flush()
+
+	// without any lines there's nothing to draw bars for
+	if !opts.Bars || len(commits) == 0 {
+		out.Write(buf.Bytes())
+		return
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	textWidth, max := 0, 0
+	for i, line := range lines {
+		if n := utf8.RuneCountInString(line); n > textWidth {
+			textWidth = n
+		}
+		if commits[i] > max {
+			max = commits[i]
+		}
+	}
+
+	barWidth := opts.Width - textWidth - 1
+	if opts.Width == 0 || barWidth < minBarWidth {
+		barWidth = minBarWidth
+	}
+
+	for i, line := range lines {
+		padding := strings.Repeat(" ", textWidth-utf8.RuneCountInString(line))
+		fmt.Fprintf(out, "%s%s %s\n", line, padding, bar(commits[i], max, barWidth))
+	}
+}
+
+// columnsLine returns the text line of the contributor's values of the columns e.g. "Contributor: gopher\t Net: 90\t"
+func columnsLine(c app.Contributor, cols []column, team bool) string {
+	fields := make([]string, len(cols))
+	for i, col := range cols {
+		title := col.Title
+		if team && col.Name == "login" {
+			title = "Team"
+		}
+		fields[i] = fmt.Sprintf("%s: %s\t", title, col.Value(c))
+	}
+	return strings.Join(fields, " ")
+}
+
+// weeklyCommitCounts returns the contributor's commits in each of the weeks
+func weeklyCommitCounts(c app.Contributor, weeks []time.Time) []int {
+	stats := c.WeeklyStats(weeks)
+	res := make([]int, len(stats))
+	for i, s := range stats {
+		res[i] = s.Commits
+	}
+	return res
+}
+
+// jsonSchemaVersion is the version of the JSON output. It's bumped whenever a field
+// is removed or its meaning changes. Adding fields doesn't change the version.
+const jsonSchemaVersion = 1
+
+// jsonReport is the JSON output. These types are kept separate from the app models
+// so that the schema only changes on purpose.
+// Times are RFC 3339 in UTC. From is null if the range has no lower bound.
+type jsonReport struct {
+	SchemaVersion int               `json:"schema_version"`
+	Repos         []string          `json:"repos"`
+	From          *time.Time        `json:"from"`
+	To            time.Time         `json:"to"`
+	GeneratedAt   time.Time         `json:"generated_at"`
+	GroupBy       string            `json:"group_by"`
+	Interval      string            `json:"interval,omitempty"`
+	Contributors  []jsonContributor `json:"contributors"`
+	Excluded      jsonExcluded      `json:"excluded"`
+}
+
+// jsonContributor is a contributor in the JSON output.
+// Login is the git author name for commits that aren't linked to a GitHub account (--precision day only).
+// Name and Team are only set for people in the --aliases file.
+// With --group-by team, each contributor is a team: Login is the team's name and Members its contributors.
+// With --interval, Periods has the stats of every period of the date range, including those without contributions.
+type jsonContributor struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Team  string `json:"team"`
+	Bot   bool   `json:"bot"`
+	jsonStats
+	jsonMetrics
+	Repos   []jsonRepoStats   `json:"repos"`
+	Members []jsonContributor `json:"members,omitempty"`
+	Periods []jsonPeriodStats `json:"periods,omitempty"`
+}
+
+// jsonPeriodStats is a contributor's stats in a period of the interval e.g. "2018-Q3" starting at 2018-07-01
+type jsonPeriodStats struct {
+	Period string    `json:"period"`
+	Start  time.Time `json:"start"`
+	jsonStats
+}
+
+type jsonRepoStats struct {
+	Repo string `json:"repo"`
+	jsonStats
+}
+
+// jsonExcluded sums up the contributors left out by --exclude-bots and the login lists
+type jsonExcluded struct {
+	Contributors int `json:"contributors"`
+	Bots         int `json:"bots"`
+	jsonStats
+}
+
+// jsonMetrics are the metrics derived from a contributor's stats, rounded to one decimal place.
+// The shares are percentages of the total of the repos in the date range, before any contributors were left out.
+type jsonMetrics struct {
+	Net            int     `json:"net"`
+	Churn          int     `json:"churn"`
+	LinesPerCommit float64 `json:"lines_per_commit"`
+	CommitShare    float64 `json:"commit_share"`
+	LineShare      float64 `json:"line_share"`
+}
+
+type jsonStats struct {
+	Commits   int `json:"commits"`
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+func newJSONContributor(c app.Contributor) jsonContributor {
+	res := jsonContributor{
+		Login:     c.Name,
+		Name:      c.DisplayName,
+		Team:      c.Team,
+		Bot:       c.Bot,
+		jsonStats: newJSONStats(c.Stats),
+		jsonMetrics: jsonMetrics{
+			Net:            c.Stats.Net(),
+			Churn:          c.Stats.Churn(),
+			LinesPerCommit: round(c.Stats.LinesPerCommit()),
+			CommitShare:    round(c.CommitShare()),
+			LineShare:      round(c.LineShare()),
+		},
+		Repos: make([]jsonRepoStats, 0, len(c.Repos)),
+	}
+	for _, rs := range c.Repos {
+		res.Repos = append(res.Repos, jsonRepoStats{Repo: rs.Repo, jsonStats: newJSONStats(rs.Stats)})
+	}
+	for _, m := range c.Members {
+		res.Members = append(res.Members, newJSONContributor(m))
+	}
+	return res
+}
+
+// round rounds f to one decimal place
+func round(f float64) float64 {
+	return math.Round(f*10) / 10
+}
+
+func newJSONStats(s app.Stats) jsonStats {
+	return jsonStats{Commits: s.Commits, Additions: s.Additions, Deletions: s.Deletions}
+}
+
+func printJSON(out io.Writer, r app.Report) error {
+	res := jsonReport{
+		SchemaVersion: jsonSchemaVersion,
+		Repos:         r.Repos,
+		To:            r.To.UTC(),
+		GeneratedAt:   r.GeneratedAt.UTC(),
+		GroupBy:       r.GroupBy,
+		Interval:      r.Interval,
+		Contributors:  make([]jsonContributor, 0, len(r.Contributors)),
+		Excluded: jsonExcluded{
+			Contributors: r.Excluded.Contributors,
+			Bots:         r.Excluded.Bots,
+			jsonStats:    newJSONStats(r.Excluded.Stats),
+		},
+	}
+	if res.Repos == nil {
+		res.Repos = []string{}
+	}
+	if res.GroupBy == "" {
+		res.GroupBy = app.GroupByContributor
+	}
+	if !r.From.IsZero() {
+		from := r.From.UTC()
+		res.From = &from
+	}
+
+	for _, c := range r.Contributors {
+		res.Contributors = append(res.Contributors, newJSONContributor(c))
+	}
+	if r.Interval != "" {
+		for i, s := range r.Series(r.Interval) {
+			res.Contributors[i].Periods = make([]jsonPeriodStats, len(s.Periods))
+			for j, p := range s.Periods {
+				res.Contributors[i].Periods[j] = jsonPeriodStats{Period: app.PeriodLabel(s.Interval, p), Start: p, jsonStats: newJSONStats(s.Stats[j])}
+			}
+		}
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+// printCSV writes a header row followed by a row per contributor, using comma to separate the fields.
+// perRepo and perWeek break each contributor down into a row per repo and/or per week, instead of their totals.
+// Fields are quoted where needed, see encoding/csv.
+func printCSV(out io.Writer, r app.Report, comma rune, perRepo, perWeek bool) error {
+	w := csv.NewWriter(out)
+	w.Comma = comma
+
+	header := []string{"login"}
+	if r.GroupBy == app.GroupByTeam {
+		header = []string{"team"}
+	}
+	if perRepo {
+		header = append(header, "repo")
+	}
+	if perWeek {
+		header = append(header, "week_beginning")
+	}
+	w.Write(append(header, "commits", "additions", "deletions", "net", "churn", "lines_per_commit", "commit_share", "line_share"))
+
+	// a contributor's shares are of RepoTotal, the total of the repos over the whole range. That isn't the
+	// total of a single repo or week, so the shares are left empty in the rows per repo or week.
+	row := func(fields []string, s app.Stats, commitShare, lineShare string) {
+		w.Write(append(fields,
+			strconv.Itoa(s.Commits), strconv.Itoa(s.Additions), strconv.Itoa(s.Deletions),
+			strconv.Itoa(s.Net()), strconv.Itoa(s.Churn()), formatDecimal(s.LinesPerCommit()),
+			commitShare, lineShare,
+		))
+	}
+	weeks := func(fields []string, weeks []app.WeekStats) {
+		for _, week := range weeks {
+			row(append(fields, week.WeekBeginning.Format("2006-01-02")), week.Stats, "", "")
+		}
+	}
+
+	for _, c := range r.Contributors {
+		switch {
+		case perRepo && perWeek:
+			for _, rs := range c.Repos {
+				weeks([]string{c.Name, rs.Repo}, rs.Weeks)
+			}
+		case perRepo:
+			for _, rs := range c.Repos {
+				row([]string{c.Name, rs.Repo}, rs.Stats, "", "")
+			}
+		case perWeek:
+			weeks([]string{c.Name}, c.Weeks)
+		default:
+			row([]string{c.Name}, c.Stats, formatDecimal(c.CommitShare()), formatDecimal(c.LineShare()))
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// loginPattern matches GitHub logins. Anything else e.g. a git author name can't be linked to a profile.
+var loginPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?$`)
+
+// printMarkdown writes a GitHub flavoured Markdown table of the contributors, with a title line.
+// cols are the names of the columns, in order; the default columns are used if empty.
+// If profileURL is set, logins link to their profile at profileURL/login.
+func printMarkdown(out io.Writer, r app.Report, cols []string, profileURL string) error {
+	if len(cols) == 0 {
+		cols = defaultColumnNames()
+	}
+	table, err := findColumns(cols)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "### Contributors to %s %s\n\n", strings.Join(r.Repos, ", "), describeRange(r.From, r.To))
+
+	teams := r.GroupBy == app.GroupByTeam
+	titles := make([]string, len(table))
+	aligns := make([]string, len(table))
+	for i, col := range table {
+		titles[i] = col.Title
+		if teams && col.Name == "login" {
+			titles[i] = "Team"
+		}
+		aligns[i] = "---"
+		if col.Numeric {
+			aligns[i] = "--:"
+		}
+	}
+	fmt.Fprintf(out, "| %s |\n| %s |\n", strings.Join(titles, " | "), strings.Join(aligns, " | "))
+
+	for _, c := range r.Contributors {
+		cells := make([]string, len(table))
+		for i, col := range table {
+			cells[i] = escapeMarkdown(col.Value(c))
+			if col.Name == "login" && profileURL != "" && !teams && loginPattern.MatchString(c.Name) {
+				cells[i] = fmt.Sprintf("[%s](%s/%s)", cells[i], profileURL, c.Name)
+			}
+		}
+		fmt.Fprintf(out, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return nil
+}
+
+// markdownEscaper escapes the characters that would break a table or add formatting
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", "&lt;", ">", "&gt;",
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// describeRange describes the date range of a report e.g. "from 2018-06-03 to 2018-07-01"
+func describeRange(from, to time.Time) string {
+	if from.IsZero() {
+		return "up to " + to.Format("2006-01-02")
+	}
+	return fmt.Sprintf("from %s to %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+}
+
+// webURL returns the root of the GitHub web site for the given API URL
+// e.g. https://github.com for https://api.github.com and https://[hostname] for https://[hostname]/api/v3
+func webURL(apiURL string) string {
+	if apiURL == githubBaseURL {
+		return "https://github.com"
+	}
+	return strings.TrimSuffix(apiURL, "/api/v3")
+}
@@ -0,0 +1,532 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+// testRepoTotal is the total of the contributors of testReport, including the one excluded
+var testRepoTotal = app.Stats{Commits: 8, Additions: 165, Deletions: 70}
+
+// testReport is the report used by the output tests
+var testReport = app.Report{
+	Repos:       []string{"test-owner/test-repo", "test-owner/other-repo"},
+	From:        time.Date(2018, 6, 3, 0, 0, 0, 0, time.UTC),
+	To:          time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC),
+	GeneratedAt: time.Date(2018, 7, 24, 12, 30, 0, 0, time.UTC),
+	Contributors: []app.Contributor{
+		{
+			Name:      "test-user-1",
+			Stats:     app.Stats{Commits: 3, Additions: 120, Deletions: 30},
+			RepoTotal: testRepoTotal,
+			Repos: []app.RepoStats{
+				{
+					Repo:  "test-owner/test-repo",
+					Stats: app.Stats{Commits: 2, Additions: 100, Deletions: 10},
+					Weeks: []app.WeekStats{
+						{WeekBeginning: time.Date(2018, 6, 3, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Commits: 1, Additions: 60, Deletions: 5}},
+						{WeekBeginning: time.Date(2018, 6, 17, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Commits: 1, Additions: 40, Deletions: 5}},
+					},
+				},
+				{
+					Repo:  "test-owner/other-repo",
+					Stats: app.Stats{Commits: 1, Additions: 20, Deletions: 20},
+					Weeks: []app.WeekStats{
+						{WeekBeginning: time.Date(2018, 6, 17, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Commits: 1, Additions: 20, Deletions: 20}},
+					},
+				},
+			},
+			Weeks: []app.WeekStats{
+				{WeekBeginning: time.Date(2018, 6, 3, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Commits: 1, Additions: 60, Deletions: 5}},
+				{WeekBeginning: time.Date(2018, 6, 17, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Commits: 2, Additions: 60, Deletions: 25}},
+			},
+		},
+		{
+			// a git author name, as used for commits that aren't linked to a GitHub account
+			Name:      "Knope, Leslie",
+			Stats:     app.Stats{Commits: 1, Additions: 5, Deletions: 0},
+			RepoTotal: testRepoTotal,
+			Repos: []app.RepoStats{
+				{
+					Repo:  "test-owner/test-repo",
+					Stats: app.Stats{Commits: 1, Additions: 5, Deletions: 0},
+					Weeks: []app.WeekStats{
+						{WeekBeginning: time.Date(2018, 6, 24, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Commits: 1, Additions: 5, Deletions: 0}},
+					},
+				},
+			},
+			Weeks: []app.WeekStats{
+				{WeekBeginning: time.Date(2018, 6, 24, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Commits: 1, Additions: 5, Deletions: 0}},
+			},
+		},
+	},
+	Excluded: app.Excluded{Contributors: 1, Bots: 1, Stats: app.Stats{Commits: 4, Additions: 40, Deletions: 40}},
+}
+
+func TestPrintJSON(t *testing.T) {
+	expect := `{
+  "schema_version": 1,
+  "repos": [
+    "test-owner/test-repo",
+    "test-owner/other-repo"
+  ],
+  "from": "2018-06-03T00:00:00Z",
+  "to": "2018-07-01T00:00:00Z",
+  "generated_at": "2018-07-24T12:30:00Z",
+  "group_by": "contributor",
+  "contributors": [
+    {
+      "login": "test-user-1",
+      "name": "",
+      "team": "",
+      "bot": false,
+      "commits": 3,
+      "additions": 120,
+      "deletions": 30,
+      "net": 90,
+      "churn": 150,
+      "lines_per_commit": 50,
+      "commit_share": 37.5,
+      "line_share": 63.8,
+      "repos": [
+        {
+          "repo": "test-owner/test-repo",
+          "commits": 2,
+          "additions": 100,
+          "deletions": 10
+        },
+        {
+          "repo": "test-owner/other-repo",
+          "commits": 1,
+          "additions": 20,
+          "deletions": 20
+        }
+      ]
+    },
+    {
+      "login": "Knope, Leslie",
+      "name": "",
+      "team": "",
+      "bot": false,
+      "commits": 1,
+      "additions": 5,
+      "deletions": 0,
+      "net": 5,
+      "churn": 5,
+      "lines_per_commit": 5,
+      "commit_share": 12.5,
+      "line_share": 2.1,
+      "repos": [
+        {
+          "repo": "test-owner/test-repo",
+          "commits": 1,
+          "additions": 5,
+          "deletions": 0
+        }
+      ]
+    }
+  ],
+  "excluded": {
+    "contributors": 1,
+    "bots": 1,
+    "commits": 4,
+    "additions": 40,
+    "deletions": 40
+  }
+}
+`
+
+	var buf bytes.Buffer
+	if err := printJSON(&buf, testReport); err != nil {
+		t.Fatalf("printJSON: Unexpected Error: %v", err)
+	}
+	if buf.String() != expect {
+		t.Errorf("printJSON:\n\nhave:\n%s\n\nwant:\n%s", buf.String(), expect)
+	}
+}
+
+func TestPrintJSONEmpty(t *testing.T) {
+	expect := `{
+  "schema_version": 1,
+  "repos": [],
+  "from": null,
+  "to": "2018-07-01T00:00:00Z",
+  "generated_at": "2018-07-24T12:30:00Z",
+  "group_by": "contributor",
+  "contributors": [],
+  "excluded": {
+    "contributors": 0,
+    "bots": 0,
+    "commits": 0,
+    "additions": 0,
+    "deletions": 0
+  }
+}
+`
+
+	var buf bytes.Buffer
+	err := printJSON(&buf, app.Report{To: testReport.To, GeneratedAt: testReport.GeneratedAt})
+	if err != nil {
+		t.Fatalf("printJSON: Unexpected Error: %v", err)
+	}
+	if buf.String() != expect {
+		t.Errorf("printJSON:\n\nhave:\n%s\n\nwant:\n%s", buf.String(), expect)
+	}
+}
+
+func TestPrintCSV(t *testing.T) {
+	ts := []struct {
+		Name    string
+		Comma   rune
+		PerRepo bool
+		PerWeek bool
+		Expect  string
+	}{
+		{
+			Name:  "Totals",
+			Comma: ',',
+			Expect: "login,commits,additions,deletions,net,churn,lines_per_commit,commit_share,line_share\n" +
+				"test-user-1,3,120,30,90,150,50.0,37.5,63.8\n" +
+				"\"Knope, Leslie\",1,5,0,5,5,5.0,12.5,2.1\n",
+		},
+		{
+			Name:    "Per Repo",
+			Comma:   ',',
+			PerRepo: true,
+			Expect: "login,repo,commits,additions,deletions,net,churn,lines_per_commit,commit_share,line_share\n" +
+				"test-user-1,test-owner/test-repo,2,100,10,90,110,55.0,,\n" +
+				"test-user-1,test-owner/other-repo,1,20,20,0,40,40.0,,\n" +
+				"\"Knope, Leslie\",test-owner/test-repo,1,5,0,5,5,5.0,,\n",
+		},
+		{
+			Name:    "Per Week",
+			Comma:   ',',
+			PerWeek: true,
+			Expect: "login,week_beginning,commits,additions,deletions,net,churn,lines_per_commit,commit_share,line_share\n" +
+				"test-user-1,2018-06-03,1,60,5,55,65,65.0,,\n" +
+				"test-user-1,2018-06-17,2,60,25,35,85,42.5,,\n" +
+				"\"Knope, Leslie\",2018-06-24,1,5,0,5,5,5.0,,\n",
+		},
+		{
+			Name:    "Per Repo Per Week",
+			Comma:   ',',
+			PerRepo: true,
+			PerWeek: true,
+			Expect: "login,repo,week_beginning,commits,additions,deletions,net,churn,lines_per_commit,commit_share,line_share\n" +
+				"test-user-1,test-owner/test-repo,2018-06-03,1,60,5,55,65,65.0,,\n" +
+				"test-user-1,test-owner/test-repo,2018-06-17,1,40,5,35,45,45.0,,\n" +
+				"test-user-1,test-owner/other-repo,2018-06-17,1,20,20,0,40,40.0,,\n" +
+				"\"Knope, Leslie\",test-owner/test-repo,2018-06-24,1,5,0,5,5,5.0,,\n",
+		},
+		{
+			Name:  "TSV",
+			Comma: '\t',
+			Expect: "login\tcommits\tadditions\tdeletions\tnet\tchurn\tlines_per_commit\tcommit_share\tline_share\n" +
+				"test-user-1\t3\t120\t30\t90\t150\t50.0\t37.5\t63.8\n" +
+				"Knope, Leslie\t1\t5\t0\t5\t5\t5.0\t12.5\t2.1\n",
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := printCSV(&buf, testReport, tc.Comma, tc.PerRepo, tc.PerWeek); err != nil {
+				t.Fatalf("printCSV: Unexpected Error: %v", err)
+			}
+			if buf.String() != tc.Expect {
+				t.Errorf("printCSV:\n\nhave:\n%s\n\nwant:\n%s", buf.String(), tc.Expect)
+			}
+		})
+	}
+}
+
+func TestPrintMarkdown(t *testing.T) {
+	ts := []struct {
+		Name       string
+		Report     app.Report
+		Columns    []string
+		ProfileURL string
+		Expect     string
+	}{
+		{
+			Name:   "Default Columns",
+			Report: testReport,
+			Expect: "### Contributors to test-owner/test-repo, test-owner/other-repo from 2018-06-03 to 2018-07-01\n\n" +
+				"| Contributor | Commits | Additions | Deletions |\n" +
+				"| --- | --: | --: | --: |\n" +
+				"| test-user-1 | 3 | 120 | 30 |\n" +
+				"| Knope, Leslie | 1 | 5 | 0 |\n",
+		},
+		{
+			Name:       "Columns and Links",
+			Report:     testReport,
+			Columns:    []string{"commits", "login"},
+			ProfileURL: "https://github.com",
+			Expect: "### Contributors to test-owner/test-repo, test-owner/other-repo from 2018-06-03 to 2018-07-01\n\n" +
+				"| Commits | Contributor |\n" +
+				"| --: | --- |\n" +
+				"| 3 | [test-user-1](https://github.com/test-user-1) |\n" +
+				"| 1 | Knope, Leslie |\n",
+		},
+		{
+			Name: "Name and Team Columns",
+			Report: app.Report{
+				Repos: []string{"test-owner/test-repo"},
+				To:    testReport.To,
+				Contributors: []app.Contributor{
+					{Name: "Leslie-Knope", DisplayName: "Leslie Knope", Team: "parks", Stats: app.Stats{Commits: 4}},
+					{Name: "test-user-1", Stats: app.Stats{Commits: 3}},
+				},
+			},
+			Columns: []string{"name", "login", "team", "commits"},
+			Expect: "### Contributors to test-owner/test-repo up to 2018-07-01\n\n" +
+				"| Name | Contributor | Team | Commits |\n" +
+				"| --- | --- | --- | --: |\n" +
+				"| Leslie Knope | Leslie-Knope | parks | 4 |\n" +
+				"|  | test-user-1 |  | 3 |\n",
+		},
+		{
+			Name:    "Derived Columns",
+			Report:  testReport,
+			Columns: []string{"login", "net", "churn", "lines_per_commit", "commit_share", "line_share"},
+			Expect: "### Contributors to test-owner/test-repo, test-owner/other-repo from 2018-06-03 to 2018-07-01\n\n" +
+				"| Contributor | Net | Churn | Lines per commit | Commit share | Line share |\n" +
+				"| --- | --: | --: | --: | --: | --: |\n" +
+				"| test-user-1 | 90 | 150 | 50.0 | 37.5% | 63.8% |\n" +
+				"| Knope, Leslie | 5 | 5 | 5.0 | 12.5% | 2.1% |\n",
+		},
+		{
+			Name: "Escaping",
+			Report: app.Report{
+				Repos:        []string{"test-owner/test-repo"},
+				To:           testReport.To,
+				Contributors: []app.Contributor{{Name: "a|b_c*"}},
+			},
+			Columns: []string{"login"},
+			Expect: "### Contributors to test-owner/test-repo up to 2018-07-01\n\n" +
+				"| Contributor |\n" +
+				"| --- |\n" +
+				"| a\\|b\\_c\\* |\n",
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := printMarkdown(&buf, tc.Report, tc.Columns, tc.ProfileURL); err != nil {
+				t.Fatalf("printMarkdown: Unexpected Error: %v", err)
+			}
+			if buf.String() != tc.Expect {
+				t.Errorf("printMarkdown:\n\nhave:\n%s\n\nwant:\n%s", buf.String(), tc.Expect)
+			}
+		})
+	}
+}
+
+func TestWebURL(t *testing.T) {
+	if have := webURL(githubBaseURL); have != "https://github.com" {
+		t.Errorf("webURL: have %s want https://github.com", have)
+	}
+	if have := webURL("https://ghe.corp/api/v3"); have != "https://ghe.corp" {
+		t.Errorf("webURL: have %s want https://ghe.corp", have)
+	}
+}
+
+func TestPrintHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printHTML(&buf, testReport, app.SortCommits); err != nil {
+		t.Fatalf("printHTML: Unexpected Error: %v", err)
+	}
+
+	for _, want := range []string{
+		"<title>Contributors to test-owner/test-repo, test-owner/other-repo</title>",
+		"from 2018-06-03 to 2018-07-01",
+		"<tr><td>Knope, Leslie</td><td class=\"num\">1</td><td class=\"num\">5</td><td class=\"num\">0</td><td class=\"num\">5</td><td class=\"num\">5</td><td class=\"num\">5.0</td><td class=\"num\">12.5</td><td class=\"num\">2.1</td></tr>",
+		"<tr><td>Total</td><td class=\"num\">4</td><td class=\"num\">125</td><td class=\"num\">30</td><td class=\"num\">95</td><td class=\"num\">155</td><td class=\"num\">38.8</td><td></td><td></td></tr>",
+		"Commits per week",
+		"Additions and deletions",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("printHTML: output doesn't contain %q", want)
+		}
+	}
+
+	// must work offline
+	for _, external := range []string{"src=", "href=", "@import", "url("} {
+		if strings.Contains(buf.String(), external) {
+			t.Errorf("printHTML: output contains an external resource (%s)", external)
+		}
+	}
+}
+
+func TestTopContributors(t *testing.T) {
+	cs := []app.Contributor{
+		{Name: "a", Stats: app.Stats{Commits: 1}},
+		{Name: "b", Stats: app.Stats{Commits: 3}},
+		{Name: "c", Stats: app.Stats{Commits: 1}},
+		{Name: "d", Stats: app.Stats{Commits: 2}},
+	}
+
+	top, rest := topContributors(cs, 3, func(s app.Stats) int { return s.Commits })
+	names := func(cs []app.Contributor) string {
+		var res []string
+		for _, c := range cs {
+			res = append(res, c.Name)
+		}
+		return strings.Join(res, ",")
+	}
+	if names(top) != "b,d,a" || names(rest) != "c" {
+		t.Errorf("topContributors: have %s and %s want b,d,a and c", names(top), names(rest))
+	}
+}
+
+func TestPrintStats(t *testing.T) {
+	cols, err := findColumns([]string{"login", "churn", "lines_per_commit", "commit_share"})
+	if err != nil {
+		t.Fatalf("findColumns: Unexpected Error: %v", err)
+	}
+
+	ts := []struct {
+		Name   string
+		Opts   textOpts
+		Expect string
+	}{
+		{
+			Name: "Plain",
+			Expect: "Contributor: test-user-1     Commits: 3   Additions: 120   Deletions: 30  \n" +
+				"Contributor: Knope, Leslie   Commits: 1   Additions: 5     Deletions: 0   \n",
+		},
+		{
+			Name: "Per Repo",
+			Opts: textOpts{PerRepo: true},
+			Expect: "Contributor: test-user-1        Commits: 3   Additions: 120   Deletions: 30  \n" +
+				"  Repo: test-owner/test-repo    Commits: 2   Additions: 100   Deletions: 10  \n" +
+				"  Repo: test-owner/other-repo   Commits: 1   Additions: 20    Deletions: 20  \n" +
+				"Contributor: Knope, Leslie      Commits: 1   Additions: 5     Deletions: 0   \n" +
+				"  Repo: test-owner/test-repo    Commits: 1   Additions: 5     Deletions: 0   \n",
+		},
+		{
+			// weeks beginning 2018-06-03, 10, 17 and 24
+			Name: "Spark",
+			Opts: textOpts{Spark: true},
+			Expect: "Contributor: test-user-1     Commits: 3   Additions: 120   Deletions: 30   ▄ █   \n" +
+				"Contributor: Knope, Leslie   Commits: 1   Additions: 5     Deletions: 0       █  \n",
+		},
+		{
+			// the text is 74 wide, leaving 25 for the bars
+			Name: "Bars",
+			Opts: textOpts{Bars: true, Width: 100},
+			Expect: "Contributor: test-user-1     Commits: 3   Additions: 120   Deletions: 30   █████████████████████████\n" +
+				"Contributor: Knope, Leslie   Commits: 1   Additions: 5     Deletions: 0    ████████▎\n",
+		},
+		{
+			Name: "Columns",
+			Opts: textOpts{Columns: cols},
+			Expect: "Contributor: test-user-1     Churn: 150   Lines per commit: 50.0   Commit share: 37.5%  \n" +
+				"Contributor: Knope, Leslie   Churn: 5     Lines per commit: 5.0    Commit share: 12.5%  \n",
+		},
+		{
+			Name: "Narrow Bars",
+			Opts: textOpts{Bars: true, Width: 40},
+			Expect: "Contributor: test-user-1     Commits: 3   Additions: 120   Deletions: 30   ██████████\n" +
+				"Contributor: Knope, Leslie   Commits: 1   Additions: 5     Deletions: 0    ███▎\n",
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printStats(&buf, testReport, tc.Opts)
+			if buf.String() != tc.Expect {
+				t.Errorf("printStats:\n\nhave:\n%q\n\nwant:\n%q", buf.String(), tc.Expect)
+			}
+		})
+	}
+
+	t.Run("Empty Bars", func(t *testing.T) {
+		var buf bytes.Buffer
+		printStats(&buf, app.Report{}, textOpts{Bars: true, Width: 100})
+		if buf.String() != "" {
+			t.Errorf("printStats: have %q want no output for a report without contributors", buf.String())
+		}
+	})
+}
+
+func TestPrintTeams(t *testing.T) {
+	r := testReport
+	r.GroupBy = app.GroupByTeam
+	r.Contributors = app.GroupByTeams(r.Contributors, []app.TeamMembers{{Team: "parks", Members: []string{"test-user-1"}}})
+
+	ts := []struct {
+		Name   string
+		Print  func(out *bytes.Buffer) error
+		Expect string
+	}{
+		{
+			Name: "Text",
+			Print: func(out *bytes.Buffer) error {
+				printStats(out, r, textOpts{})
+				return nil
+			},
+			Expect: "Team: parks               Commits: 3   Additions: 120   Deletions: 30  \n" +
+				"  Member: test-user-1     Commits: 3   Additions: 120   Deletions: 30  \n" +
+				"Team: (no team)           Commits: 1   Additions: 5     Deletions: 0   \n" +
+				"  Member: Knope, Leslie   Commits: 1   Additions: 5     Deletions: 0   \n",
+		},
+		{
+			Name:  "CSV",
+			Print: func(out *bytes.Buffer) error { return printCSV(out, r, ',', false, false) },
+			Expect: "team,commits,additions,deletions,net,churn,lines_per_commit,commit_share,line_share\n" +
+				"parks,3,120,30,90,150,50.0,37.5,63.8\n" +
+				"(no team),1,5,0,5,5,5.0,12.5,2.1\n",
+		},
+		{
+			Name: "Markdown",
+			Print: func(out *bytes.Buffer) error {
+				return printMarkdown(out, r, []string{"login", "commits"}, "https://github.com")
+			},
+			Expect: "### Contributors to test-owner/test-repo, test-owner/other-repo from 2018-06-03 to 2018-07-01\n\n" +
+				"| Team | Commits |\n" +
+				"| --- | --: |\n" +
+				"| parks | 3 |\n" +
+				"| (no team) | 1 |\n",
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tc.Print(&buf); err != nil {
+				t.Fatalf("Unexpected Error: %v", err)
+			}
+			if buf.String() != tc.Expect {
+				t.Errorf("have:\n%q\n\nwant:\n%q", buf.String(), tc.Expect)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := printJSON(&buf, r); err != nil {
+		t.Fatalf("printJSON: Unexpected Error: %v", err)
+	}
+	var doc struct {
+		GroupBy      string `json:"group_by"`
+		Contributors []struct {
+			Login   string `json:"login"`
+			Members []struct {
+				Login string `json:"login"`
+			} `json:"members"`
+		} `json:"contributors"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("printJSON: invalid JSON: %v", err)
+	}
+	if doc.GroupBy != app.GroupByTeam || len(doc.Contributors) != 2 || doc.Contributors[0].Members[0].Login != "test-user-1" {
+		t.Errorf("printJSON: have %+v want the teams parks and (no team) with their members", doc)
+	}
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Person is the canonical identity of someone who contributes with one or more logins
+// - Login: the login their stats are reported under
+// - Name: their display name. Optional.
+// - Team: the team they're in. Optional.
+// - Aliases: their other logins e.g. a personal and a work account
+type Person struct {
+	Login   string
+	Name    string
+	Team    string
+	Aliases []string
+}
+
+// Aliases maps each login, canonical or alias, to the person it belongs to.
+// Logins are matched case insensitively, like GitHub does.
+type Aliases map[string]Person
+
+// NewAliases returns the aliases of the given people.
+// Returns an error if a person has no login or a login belongs to more than one person.
+func NewAliases(people []Person) (Aliases, error) {
+	res := make(Aliases)
+	for _, p := range people {
+		if p.Login == "" {
+			return nil, errors.Errorf("[NewAliases] %q has no login", p.Name)
+		}
+		for _, login := range append([]string{p.Login}, p.Aliases...) {
+			key := strings.ToLower(login)
+			if other, ok := res[key]; ok {
+				return nil, errors.Errorf("[NewAliases] login %q belongs to both %s and %s", login, other.Login, p.Login)
+			}
+			res[key] = p
+		}
+	}
+	return res, nil
+}
+
+// Person returns the person the login belongs to
+func (a Aliases) Person(login string) (Person, bool) {
+	p, ok := a[strings.ToLower(login)]
+	return p, ok
+}
+
+// Canonical returns the login the stats of the given login are reported under
+func (a Aliases) Canonical(login string) string {
+	if p, ok := a.Person(login); ok {
+		return p.Login
+	}
+	return login
+}
+
+// MergeAliases merges the contributors that are the same person into one contributor named after
+// their canonical login, summing their stats per repo and per week. DisplayName and Team are set
+// from the person. Contributors that aren't in the aliases are left as they are.
+// Contributors are returned in the order any of their logins first appear.
+func MergeAliases(cs []Contributor, a Aliases) []Contributor {
+	res := make([]Contributor, 0, len(cs))
+	index := make(map[string]int)
+
+	for _, c := range cs {
+		p, ok := a.Person(c.Name)
+		if !ok {
+			res = append(res, c)
+			continue
+		}
+
+		i, ok := index[p.Login]
+		if !ok {
+			i = len(res)
+			index[p.Login] = i
+			res = append(res, Contributor{Name: p.Login, DisplayName: p.Name, Team: p.Team})
+		}
+
+		res[i].Bot = res[i].Bot || c.Bot
+		res[i].Stats = res[i].Stats.Add(c.Stats)
+		for _, rs := range c.Repos {
+			res[i].Repos = addRepo(res[i].Repos, rs)
+		}
+		for _, w := range c.Weeks {
+			res[i].Weeks = addWeek(res[i].Weeks, w)
+		}
+	}
+	return res
+}
+
+// addRepo adds rs to the stats of the same repo in repos, or appends it if it's a new repo
+func addRepo(repos []RepoStats, rs RepoStats) []RepoStats {
+	for i := range repos {
+		if repos[i].Repo == rs.Repo {
+			repos[i].Stats = repos[i].Stats.Add(rs.Stats)
+			for _, w := range rs.Weeks {
+				repos[i].Weeks = addWeek(repos[i].Weeks, w)
+			}
+			return repos
+		}
+	}
+	// copied so that adding to it later doesn't modify the weeks of the contributor it came from
+	rs.Weeks = append([]WeekStats(nil), rs.Weeks...)
+	return append(repos, rs)
+}
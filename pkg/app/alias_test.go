@@ -0,0 +1,113 @@
+package app_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestNewAliases(t *testing.T) {
+	ts := []struct {
+		Name      string
+		Input     []app.Person
+		ExpectErr error
+	}{
+		{
+			Name: "Valid",
+			Input: []app.Person{
+				{Login: "Leslie-Knope", Name: "Leslie Knope", Aliases: []string{"lknope-corp"}},
+				{Login: "Ron-Swanson"},
+			},
+		},
+		{
+			Name:      "No Login",
+			Input:     []app.Person{{Name: "Leslie Knope", Aliases: []string{"lknope-corp"}}},
+			ExpectErr: fmt.Errorf(`[NewAliases] "Leslie Knope" has no login`),
+		},
+		{
+			// logins are case insensitive
+			Name: "Shared Login",
+			Input: []app.Person{
+				{Login: "Leslie-Knope", Aliases: []string{"parks-dept"}},
+				{Login: "Ron-Swanson", Aliases: []string{"Parks-Dept"}},
+			},
+			ExpectErr: fmt.Errorf(`[NewAliases] login "Parks-Dept" belongs to both Leslie-Knope and Ron-Swanson`),
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := app.NewAliases(tc.Input)
+			if tc.ExpectErr != nil {
+				if err == nil || err.Error() != tc.ExpectErr.Error() {
+					t.Fatalf("NewAliases: Have `err`: %v want: %v", err, tc.ExpectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewAliases: Unexpected Error: %v", err)
+			}
+		})
+	}
+}
+
+func TestMergeAliases(t *testing.T) {
+	aliases, err := app.NewAliases([]app.Person{
+		{Login: "Leslie-Knope", Name: "Leslie Knope", Team: "parks", Aliases: []string{"lknope-corp", "Knope, Leslie"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := []app.Contributor{
+		{
+			Name:  "LKnope-Corp",
+			Stats: app.Stats{Additions: 10, Deletions: 5, Commits: 2},
+			Repos: []app.RepoStats{
+				{Repo: "test-owner/repo-a", Stats: app.Stats{Additions: 10, Deletions: 5, Commits: 2}, Weeks: []app.WeekStats{week(6, 10, app.Stats{Additions: 10, Deletions: 5, Commits: 2})}},
+			},
+			Weeks: []app.WeekStats{week(6, 10, app.Stats{Additions: 10, Deletions: 5, Commits: 2})},
+		},
+		{Name: "Ron-Swanson", Stats: app.Stats{Additions: 1, Deletions: 1, Commits: 1}},
+		{
+			Name:  "Leslie-Knope",
+			Stats: app.Stats{Additions: 7, Deletions: 0, Commits: 3},
+			Repos: []app.RepoStats{
+				{Repo: "test-owner/repo-a", Stats: app.Stats{Additions: 4, Commits: 1}, Weeks: []app.WeekStats{week(6, 17, app.Stats{Additions: 4, Commits: 1})}},
+				{Repo: "test-owner/repo-b", Stats: app.Stats{Additions: 3, Commits: 2}, Weeks: []app.WeekStats{week(6, 10, app.Stats{Additions: 3, Commits: 2})}},
+			},
+			Weeks: []app.WeekStats{week(6, 10, app.Stats{Additions: 3, Commits: 2}), week(6, 17, app.Stats{Additions: 4, Commits: 1})},
+		},
+		{Name: "Knope, Leslie", Stats: app.Stats{Commits: 1}},
+	}
+
+	res := app.MergeAliases(input, aliases)
+	want := []app.Contributor{
+		{
+			Name:        "Leslie-Knope",
+			DisplayName: "Leslie Knope",
+			Team:        "parks",
+			Stats:       app.Stats{Additions: 17, Deletions: 5, Commits: 6},
+			Repos: []app.RepoStats{
+				{
+					Repo:  "test-owner/repo-a",
+					Stats: app.Stats{Additions: 14, Deletions: 5, Commits: 3},
+					Weeks: []app.WeekStats{week(6, 10, app.Stats{Additions: 10, Deletions: 5, Commits: 2}), week(6, 17, app.Stats{Additions: 4, Commits: 1})},
+				},
+				{Repo: "test-owner/repo-b", Stats: app.Stats{Additions: 3, Commits: 2}, Weeks: []app.WeekStats{week(6, 10, app.Stats{Additions: 3, Commits: 2})}},
+			},
+			Weeks: []app.WeekStats{week(6, 10, app.Stats{Additions: 13, Deletions: 5, Commits: 4}), week(6, 17, app.Stats{Additions: 4, Commits: 1})},
+		},
+		{Name: "Ron-Swanson", Stats: app.Stats{Additions: 1, Deletions: 1, Commits: 1}},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("MergeAliases:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+
+	if len(input[0].Repos[0].Weeks) != 1 {
+		t.Error("MergeAliases: the input was modified")
+	}
+}
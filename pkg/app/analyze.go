@@ -0,0 +1,169 @@
+package app
+
+import (
+	"sort"
+	"time"
+)
+
+// AllRepos is the Repo of the RepoAnalysis of all the repos together
+const AllRepos = "(all repositories)"
+
+// Analysis is our apps model of the output of the analyze command:
+// how concentrated the contributions to each repo are, see Concentration.
+// - Metric: the stat the contributions are measured by, see Metric
+// - Coverage: the percentage of the metric the bus factor covers e.g. 50
+// - Interval: the interval of the Trend of each repo. Empty for no trends.
+// - Window: the length of the windows of the trends, in weeks
+type Analysis struct {
+	From        time.Time
+	To          time.Time
+	GeneratedAt time.Time
+	Metric      string
+	Coverage    float64
+	Interval    string
+	Window      int
+	Repos       []RepoAnalysis
+}
+
+// RepoAnalysis is the concentration of the contributions to a repo (or AllRepos) over the date range,
+// and over each of the rolling windows of the trend. See RollingWindows.
+type RepoAnalysis struct {
+	Repo string
+	Concentration
+	Trend []WindowConcentration
+}
+
+// WindowConcentration is the concentration of the contributions to a repo in one of the rolling windows of a trend
+type WindowConcentration struct {
+	From time.Time
+	To   time.Time
+	Concentration
+}
+
+// Concentration is our apps model of how concentrated the contributions are among the contributors
+// - Contributors: how many contributors contributed to the metric
+// - Total: the total of the metric
+// - BusFactor: the fewest contributors that between them contributed the coverage e.g. 50% of the metric.
+// The lower it is, the more the repo depends on a few people.
+// - Gini: the Gini coefficient of the contributions, from 0 when everyone contributed the same
+// towards 1 when one contributor did almost everything
+// - Herfindahl: the Herfindahl index i.e. the sum of the squares of each contributor's share of the metric,
+// from 1/Contributors when everyone contributed the same to 1 when one contributor did everything
+type Concentration struct {
+	Contributors int
+	Total        int
+	BusFactor    int
+	Gini         float64
+	Herfindahl   float64
+}
+
+// CalcConcentration calculates the concentration of the contributors' metric, see Metric.
+// coverage is the percentage of the metric the bus factor covers e.g. 50.
+// Contributors without any of the metric aren't counted. The metric must not be negative, so not SortNet.
+func CalcConcentration(cs []Contributor, metric func(s Stats) int, coverage float64) Concentration {
+	var values []int
+	var res Concentration
+	for _, c := range cs {
+		if v := metric(c.Stats); v > 0 {
+			values = append(values, v)
+			res.Total += v
+		}
+	}
+	res.Contributors = len(values)
+	if res.Total == 0 {
+		return res
+	}
+
+	// biggest first, for the bus factor
+	sort.Sort(sort.Reverse(sort.IntSlice(values)))
+	covered := 0
+	for _, v := range values {
+		covered += v
+		res.BusFactor++
+		if float64(covered)*100 >= coverage*float64(res.Total) {
+			break
+		}
+	}
+
+	n, total := float64(len(values)), float64(res.Total)
+	var weighted float64
+	for i, v := range values {
+		share := float64(v) / total
+		res.Herfindahl += share * share
+		// the rank of v in ascending order, from 1
+		rank := n - float64(i)
+		weighted += (2*rank - n - 1) * float64(v)
+	}
+	res.Gini = weighted / (n * total)
+	return res
+}
+
+// Analyze analyses the concentration of the contributions to each of the report's repos,
+// followed by AllRepos when there are several.
+// Each repo's Trend has its concentration in each of the windows, which are reports of the same repos
+// over the rolling windows (see RollingWindows). metric is the name of the stat e.g. SortCommits, see Metric.
+func Analyze(r Report, windows []Report, metric string, coverage float64) Analysis {
+	res := Analysis{
+		From:        r.From,
+		To:          r.To,
+		GeneratedAt: r.GeneratedAt,
+		Metric:      metric,
+		Coverage:    coverage,
+		Interval:    r.Interval,
+	}
+
+	repos := r.Repos
+	if len(repos) > 1 {
+		repos = append(repos[:len(repos):len(repos)], AllRepos)
+	}
+	m := Metric(metric)
+	for _, repo := range repos {
+		ra := RepoAnalysis{Repo: repo, Concentration: CalcConcentration(repoContributors(r.Contributors, repo), m, coverage)}
+		for _, w := range windows {
+			ra.Trend = append(ra.Trend, WindowConcentration{
+				From:          w.From,
+				To:            w.To,
+				Concentration: CalcConcentration(repoContributors(w.Contributors, repo), m, coverage),
+			})
+		}
+		res.Repos = append(res.Repos, ra)
+	}
+	return res
+}
+
+// repoContributors returns the contributors with their stats for the repo only, or all of their stats for AllRepos
+func repoContributors(cs []Contributor, repo string) []Contributor {
+	if repo == AllRepos {
+		return cs
+	}
+	var res []Contributor
+	for _, c := range cs {
+		for _, rs := range c.Repos {
+			if rs.Repo == repo {
+				res = append(res, Contributor{Name: c.Name, Stats: rs.Stats})
+			}
+		}
+	}
+	return res
+}
+
+// RollingWindows returns the date ranges of the rolling windows of a trend over the date range:
+// a window of the given number of weeks ending at the end of each period of the interval in the range
+// (see Report.Periods), or at the end of the range for the last period. Windows can start before the range.
+// The range needs a From, otherwise there are no windows.
+func RollingWindows(options CalcContrbutionsOpts, interval string, weeks int) []CalcContrbutionsOpts {
+	r := Report{From: options.From, To: options.To}
+	var res []CalcContrbutionsOpts
+	for _, p := range r.Periods(interval) {
+		end := nextPeriod(interval, p)
+		if !end.After(options.From) {
+			// the first week of the range can start in the period before it
+			continue
+		}
+		if end.After(options.To) {
+			end = options.To
+		}
+		res = append(res, CalcContrbutionsOpts{From: end.AddDate(0, 0, -7*weeks), To: end})
+	}
+	return res
+}
@@ -0,0 +1,149 @@
+package app_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestCalcConcentration(t *testing.T) {
+	contributors := func(commits ...int) []app.Contributor {
+		var res []app.Contributor
+		for _, c := range commits {
+			res = append(res, app.Contributor{Stats: app.Stats{Commits: c}})
+		}
+		return res
+	}
+
+	ts := []struct {
+		Name     string
+		Input    []app.Contributor
+		Coverage float64
+		Expect   app.Concentration
+	}{
+		{
+			Name:     "Even",
+			Input:    contributors(5, 5, 5, 5),
+			Coverage: 50,
+			Expect:   app.Concentration{Contributors: 4, Total: 20, BusFactor: 2, Gini: 0, Herfindahl: 0.25},
+		},
+		{
+			Name:     "One Main Contributor",
+			Input:    contributors(1, 9),
+			Coverage: 50,
+			Expect:   app.Concentration{Contributors: 2, Total: 10, BusFactor: 1, Gini: 0.4, Herfindahl: 0.82},
+		},
+		{
+			// 6 + 3 = 9 of 10 isn't 95%
+			Name:     "Coverage",
+			Input:    contributors(3, 0, 1, 6),
+			Coverage: 95,
+			Expect:   app.Concentration{Contributors: 3, Total: 10, BusFactor: 3, Gini: 1.0 / 3, Herfindahl: 0.46},
+		},
+		{
+			Name:     "Single Contributor",
+			Input:    contributors(7, 0),
+			Coverage: 50,
+			Expect:   app.Concentration{Contributors: 1, Total: 7, BusFactor: 1, Gini: 0, Herfindahl: 1},
+		},
+		{
+			Name:     "No Contributions",
+			Input:    contributors(0, 0),
+			Coverage: 50,
+			Expect:   app.Concentration{},
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			res := app.CalcConcentration(tc.Input, app.Metric(app.SortCommits), tc.Coverage)
+			// the indexes are floats, compared separately
+			if res.Contributors != tc.Expect.Contributors || res.Total != tc.Expect.Total || res.BusFactor != tc.Expect.BusFactor ||
+				math.Abs(res.Gini-tc.Expect.Gini) > 1e-9 || math.Abs(res.Herfindahl-tc.Expect.Herfindahl) > 1e-9 {
+				t.Errorf("CalcConcentration:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, tc.Expect)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	report := app.Report{
+		Repos: []string{"o/a", "o/b"},
+		From:  date(2018, 7, 1),
+		To:    date(2018, 8, 1),
+		Contributors: []app.Contributor{
+			{Name: "Ron-Swanson", Stats: app.Stats{Commits: 9, Additions: 10}, Repos: []app.RepoStats{
+				{Repo: "o/a", Stats: app.Stats{Commits: 8, Additions: 10}},
+				{Repo: "o/b", Stats: app.Stats{Commits: 1}},
+			}},
+			{Name: "Leslie-Knope", Stats: app.Stats{Commits: 3, Additions: 30}, Repos: []app.RepoStats{
+				{Repo: "o/b", Stats: app.Stats{Commits: 3, Additions: 30}},
+			}},
+		},
+	}
+	window := app.Report{
+		From: date(2018, 5, 6),
+		To:   date(2018, 8, 1),
+		Contributors: []app.Contributor{
+			{Name: "Leslie-Knope", Stats: app.Stats{Additions: 5}, Repos: []app.RepoStats{
+				{Repo: "o/a", Stats: app.Stats{Additions: 5}},
+			}},
+		},
+	}
+
+	res := app.Analyze(report, []app.Report{window}, app.SortAdditions, 50)
+
+	var repos []string
+	var busFactors []int
+	for _, ra := range res.Repos {
+		repos = append(repos, ra.Repo)
+		busFactors = append(busFactors, ra.BusFactor)
+	}
+	if want := []string{"o/a", "o/b", app.AllRepos}; !reflect.DeepEqual(repos, want) {
+		t.Errorf("Analyze: have repos %v want %v", repos, want)
+	}
+	if want := []int{1, 1, 1}; !reflect.DeepEqual(busFactors, want) {
+		t.Errorf("Analyze: have bus factors %v want %v", busFactors, want)
+	}
+	if have := res.Repos[2].Contributors; have != 2 {
+		t.Errorf("Analyze: have %d contributors to all repos want 2", have)
+	}
+
+	want := []app.WindowConcentration{{
+		From:          date(2018, 5, 6),
+		To:            date(2018, 8, 1),
+		Concentration: app.Concentration{Contributors: 1, Total: 5, BusFactor: 1, Herfindahl: 1},
+	}}
+	if !reflect.DeepEqual(res.Repos[0].Trend, want) {
+		t.Errorf("Analyze:\n\nhave trend:\n%+v\n\nwant trend:\n%+v", res.Repos[0].Trend, want)
+	}
+	if len(res.Repos[1].Trend) != 1 || res.Repos[1].Trend[0].Contributors != 0 {
+		t.Errorf("Analyze: have trend %+v want no contributors to o/b", res.Repos[1].Trend)
+	}
+}
+
+func TestRollingWindows(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	res := app.RollingWindows(app.CalcContrbutionsOpts{From: date(2018, 8, 1), To: date(2018, 10, 15)}, app.IntervalMonth, 4)
+	want := []app.CalcContrbutionsOpts{
+		{From: date(2018, 8, 4), To: date(2018, 9, 1)},
+		{From: date(2018, 9, 3), To: date(2018, 10, 1)},
+		{From: date(2018, 9, 17), To: date(2018, 10, 15)},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("RollingWindows:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+
+	if res := app.RollingWindows(app.CalcContrbutionsOpts{To: date(2018, 10, 15)}, app.IntervalMonth, 4); res != nil {
+		t.Errorf("RollingWindows: have %+v want no windows without a From", res)
+	}
+}
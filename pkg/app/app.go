@@ -4,6 +4,7 @@ package app
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/luke-davies/gh-contrib-stats/pkg/github"
@@ -11,9 +12,43 @@ import (
 )
 
 // Contributor is our apps model of a contributor
+// Repos is the breakdown of Stats per repo and is only set by MergeContributors.
+// Weeks is the breakdown of Stats per week, in order. Weeks without any contributions are left out.
+// Bot is set for bot accounts, see github.Author.IsBot.
+// DisplayName and Team are only set for people merged by MergeAliases.
+// Members is only set for teams, see GroupByTeams.
+// RepoTotal is the total stats of every contributor to the repos, the base of CommitShare and LineShare.
+// It's only set by WithRepoTotal.
 type Contributor struct {
-	Name  string
+	Name        string
+	DisplayName string
+	Team        string
+	Bot         bool
+	Stats       Stats
+	Repos       []RepoStats
+	Weeks       []WeekStats
+	Members     []Contributor
+	RepoTotal   Stats
+}
+
+// RepoStats is our apps model of a contributor's stats for a single repo
+type RepoStats struct {
+	Repo  string
 	Stats Stats
+	Weeks []WeekStats
+}
+
+// WeekStats is our apps model of a contributor's stats for a single week
+// WeekBeginning is a Sunday, see WeekBeginning.
+type WeekStats struct {
+	WeekBeginning time.Time
+	Stats         Stats
+}
+
+// RepoContributors holds the contributors of a single repo (owner/name)
+type RepoContributors struct {
+	Repo         string
+	Contributors []Contributor
 }
 
 // Stats is our apps model of contributor stats
@@ -23,6 +58,42 @@ type Stats struct {
 	Commits   int
 }
 
+// Add returns the sum of both stats
+func (s Stats) Add(o Stats) Stats {
+	return Stats{
+		Additions: s.Additions + o.Additions,
+		Deletions: s.Deletions + o.Deletions,
+		Commits:   s.Commits + o.Commits,
+	}
+}
+
+// Sub returns the difference of both stats i.e. s minus o
+func (s Stats) Sub(o Stats) Stats {
+	return Stats{
+		Additions: s.Additions - o.Additions,
+		Deletions: s.Deletions - o.Deletions,
+		Commits:   s.Commits - o.Commits,
+	}
+}
+
+// Net returns the net lines added i.e. additions minus deletions
+func (s Stats) Net() int {
+	return s.Additions - s.Deletions
+}
+
+// Churn returns the lines changed i.e. additions plus deletions
+func (s Stats) Churn() int {
+	return s.Additions + s.Deletions
+}
+
+// LinesPerCommit returns the average lines changed (churn) per commit, or zero without commits
+func (s Stats) LinesPerCommit() float64 {
+	if s.Commits == 0 {
+		return 0
+	}
+	return float64(s.Churn()) / float64(s.Commits)
+}
+
 func (s Stats) String() string {
 	return fmt.Sprintf(
 		"Commits: %d\t Additions: %d\t Deletions: %d\t",
@@ -30,8 +101,34 @@ func (s Stats) String() string {
 	)
 }
 
+// CommitShare returns the contributor's commits as a percentage of RepoTotal's e.g. 12.5
+func (c Contributor) CommitShare() float64 {
+	return Share(c.Stats.Commits, c.RepoTotal.Commits)
+}
+
+// LineShare returns the lines the contributor changed (churn) as a percentage of RepoTotal's e.g. 12.5
+func (c Contributor) LineShare() float64 {
+	return Share(c.Stats.Churn(), c.RepoTotal.Churn())
+}
+
+// Share returns part as a percentage of total, or zero if total is zero
+func Share(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+// Label returns the login followed by the display name, if there is one e.g. "Leslie-Knope (Leslie Knope)"
+func (c Contributor) Label() string {
+	if c.DisplayName != "" {
+		return fmt.Sprintf("%s (%s)", c.Name, c.DisplayName)
+	}
+	return c.Name
+}
+
 func (c Contributor) String() string {
-	return fmt.Sprintf("Contributor: %s\t %s\n", c.Name, c.Stats)
+	return fmt.Sprintf("Contributor: %s\t %s\n", c.Label(), c.Stats)
 }
 
 // CalcContrbutionsOpts contains available options for CalcContributions
@@ -51,21 +148,99 @@ type CalcContrbutionsOpts struct {
 // therefore the range can only be applied against week beginning.
 // i.e stats with week-beginnings between the date range are included.
 // This means that a date range of a monday to saturday (6 days) will result in no data.
+// Use CalcCommitContributions for exact ranges.
 func CalcContributions(contributor github.ContributorStats, options CalcContrbutionsOpts) Contributor {
-	res := Contributor{Name: contributor.Author.Login} // initialised with zero values for stats
+	res := Contributor{Name: contributor.Author.Login, Bot: contributor.Author.IsBot()} // initialised with zero values for stats
 
 	for _, w := range contributor.Weeks {
 		wb := time.Unix(w.WeekBeginning, 0)
 		// No guarantee that weeks are in order so can't stop early :(
-		if (options.From.Equal(wb) || wb.After(options.From)) && wb.Before(options.To) {
-			res.Stats.Additions += w.Additions
-			res.Stats.Deletions += w.Deletions
-			res.Stats.Commits += w.Commits
+		if inRange(wb, options) {
+			s := Stats{Additions: w.Additions, Deletions: w.Deletions, Commits: w.Commits}
+			res.Stats = res.Stats.Add(s)
+			if s != (Stats{}) {
+				res.Weeks = addWeek(res.Weeks, WeekStats{WeekBeginning: wb.UTC(), Stats: s})
+			}
 		}
 	}
 	return res
 }
 
+// CalcCommitContributions calculates the total stats (commits, additions and deletions) of each
+// author of the given commits for the given options.
+//
+// Unlike CalcContributions, the range is applied against the exact time each commit was authored.
+// The commits must include their stats (see github.Client.GetCommit).
+// Authors are identified by their GitHub login or, if the commit isn't linked to a GitHub account, their git name.
+// Contributors are returned in the order they first appear in the commits.
+func CalcCommitContributions(commits []github.Commit, options CalcContrbutionsOpts) []Contributor {
+	res := make([]Contributor, 0)
+	index := make(map[string]int)
+
+	for _, c := range commits {
+		if !inRange(c.Commit.Author.Date, options) {
+			continue
+		}
+
+		author := github.Author{Login: c.Commit.Author.Name}
+		if c.Author != nil && c.Author.Login != "" {
+			author = *c.Author
+		}
+
+		i, ok := index[author.Login]
+		if !ok {
+			i = len(res)
+			index[author.Login] = i
+			res = append(res, Contributor{Name: author.Login, Bot: author.IsBot()})
+		}
+
+		s := Stats{Additions: c.Stats.Additions, Deletions: c.Stats.Deletions, Commits: 1}
+		res[i].Stats = res[i].Stats.Add(s)
+		res[i].Weeks = addWeek(res[i].Weeks, WeekStats{WeekBeginning: WeekBeginning(c.Commit.Author.Date), Stats: s})
+	}
+	return res
+}
+
+// MergeContributors merges the contributors of each repo by name, summing their stats.
+// The Repos of each merged contributor holds their stats for each repo they contributed to.
+// Contributors are returned in the order they first appear.
+func MergeContributors(rcs []RepoContributors) []Contributor {
+	res := make([]Contributor, 0)
+	index := make(map[string]int)
+
+	for _, rc := range rcs {
+		for _, c := range rc.Contributors {
+			i, ok := index[c.Name]
+			if !ok {
+				i = len(res)
+				index[c.Name] = i
+				res = append(res, Contributor{Name: c.Name})
+			}
+
+			res[i].Bot = res[i].Bot || c.Bot
+			res[i].Stats = res[i].Stats.Add(c.Stats)
+			res[i].Repos = append(res[i].Repos, RepoStats{Repo: rc.Repo, Stats: c.Stats, Weeks: c.Weeks})
+			for _, w := range c.Weeks {
+				res[i].Weeks = addWeek(res[i].Weeks, w)
+			}
+		}
+	}
+	return res
+}
+
+// addWeek adds w to the stats of the same week in weeks, keeping weeks in order
+func addWeek(weeks []WeekStats, w WeekStats) []WeekStats {
+	i := sort.Search(len(weeks), func(i int) bool { return !weeks[i].WeekBeginning.Before(w.WeekBeginning) })
+	if i < len(weeks) && weeks[i].WeekBeginning.Equal(w.WeekBeginning) {
+		weeks[i].Stats = weeks[i].Stats.Add(w.Stats)
+		return weeks
+	}
+	weeks = append(weeks, WeekStats{})
+	copy(weeks[i+1:], weeks[i:])
+	weeks[i] = w
+	return weeks
+}
+
 // NormaliseCalcContributionsOpts normalises the given options
 func NormaliseCalcContributionsOpts(options CalcContrbutionsOpts) CalcContrbutionsOpts {
 	from := options.From // The zero value of time.Time is ok for From.
@@ -92,6 +267,21 @@ func ValidateCalcContributionsOpts(options CalcContrbutionsOpts) error {
 	return nil
 }
 
+// WithRepoTotal returns a new slice of Contributors with their RepoTotal set to the sum of the stats of all of them.
+// It's called before any contributors are filtered out, so that shares are of everything in the date range.
+func WithRepoTotal(cs []Contributor) []Contributor {
+	var total Stats
+	for _, c := range cs {
+		total = total.Add(c.Stats)
+	}
+	res := make([]Contributor, len(cs))
+	for i, c := range cs {
+		c.RepoTotal = total
+		res[i] = c
+	}
+	return res
+}
+
 // FilterContributors returns a new slice of Contributors filtered by the given function
 func FilterContributors(cs []Contributor, f func(Contributor) bool) []Contributor {
 	res := make([]Contributor, 0)
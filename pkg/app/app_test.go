@@ -9,6 +9,11 @@ import (
 	"github.com/luke-davies/gh-contrib-stats/pkg/github"
 )
 
+// week returns the stats of the week beginning on the given month and day of 2018
+func week(month time.Month, day int, s app.Stats) app.WeekStats {
+	return app.WeekStats{WeekBeginning: time.Date(2018, month, day, 0, 0, 0, 0, time.UTC), Stats: s}
+}
+
 var testContributorStats = github.ContributorStats{
 	Author: github.Author{
 		Login: "Luke-Davies",
@@ -62,6 +67,12 @@ func TestCalcContributions(t *testing.T) {
 					Deletions: 92,
 					Commits:   15,
 				},
+				Weeks: []app.WeekStats{
+					{WeekBeginning: time.Date(2018, 6, 3, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Additions: 10, Deletions: 12, Commits: 3}},
+					{WeekBeginning: time.Date(2018, 6, 10, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Additions: 11, Deletions: 14, Commits: 2}},
+					{WeekBeginning: time.Date(2018, 6, 17, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Additions: 55, Deletions: 44, Commits: 3}},
+					{WeekBeginning: time.Date(2018, 6, 24, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Additions: 33, Deletions: 22, Commits: 7}},
+				},
 			},
 		},
 		{
@@ -104,6 +115,9 @@ func TestCalcContributions(t *testing.T) {
 					Deletions: 44,
 					Commits:   3,
 				},
+				Weeks: []app.WeekStats{
+					{WeekBeginning: time.Date(2018, 6, 17, 0, 0, 0, 0, time.UTC), Stats: app.Stats{Additions: 55, Deletions: 44, Commits: 3}},
+				},
 			},
 		},
 	}
@@ -119,6 +133,48 @@ func TestCalcContributions(t *testing.T) {
 
 }
 
+func TestCalcCommitContributions(t *testing.T) {
+	commit := func(login, name string, date time.Time, additions, deletions int) github.Commit {
+		c := github.Commit{
+			Commit: github.CommitDetails{Author: github.CommitAuthor{Name: name, Date: date}},
+			Stats:  github.CommitStats{Additions: additions, Deletions: deletions},
+		}
+		if login != "" {
+			c.Author = &github.Author{Login: login}
+		}
+		return c
+	}
+
+	// monday to saturday, which would give no data with CalcContributions
+	from := time.Date(2018, 6, 18, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2018, 6, 23, 0, 0, 0, 0, time.UTC)
+
+	commits := []github.Commit{
+		commit("Luke-Davies", "Luke", time.Date(2018, 6, 22, 23, 59, 0, 0, time.UTC), 10, 2),
+		commit("Ron-Swanson", "Ron", time.Date(2018, 6, 20, 9, 0, 0, 0, time.UTC), 5, 5),
+		commit("Luke-Davies", "Luke", time.Date(2018, 6, 18, 0, 0, 0, 0, time.UTC), 1, 1),
+		commit("", "Leslie Knope", time.Date(2018, 6, 19, 9, 0, 0, 0, time.UTC), 3, 0),
+		commit("", "renovate[bot]", time.Date(2018, 6, 19, 10, 0, 0, 0, time.UTC), 1, 1),
+		commit("Luke-Davies", "Luke", time.Date(2018, 6, 23, 0, 0, 0, 0, time.UTC), 100, 100), // `to` is exclusive
+		commit("Ron-Swanson", "Ron", time.Date(2018, 6, 17, 23, 59, 0, 0, time.UTC), 100, 100),
+	}
+
+	res := app.CalcCommitContributions(commits, app.CalcContrbutionsOpts{From: from, To: to})
+	weeks := func(s app.Stats) []app.WeekStats {
+		return []app.WeekStats{{WeekBeginning: time.Date(2018, 6, 17, 0, 0, 0, 0, time.UTC), Stats: s}}
+	}
+	want := []app.Contributor{
+		{Name: "Luke-Davies", Stats: app.Stats{Additions: 11, Deletions: 3, Commits: 2}, Weeks: weeks(app.Stats{Additions: 11, Deletions: 3, Commits: 2})},
+		{Name: "Ron-Swanson", Stats: app.Stats{Additions: 5, Deletions: 5, Commits: 1}, Weeks: weeks(app.Stats{Additions: 5, Deletions: 5, Commits: 1})},
+		{Name: "Leslie Knope", Stats: app.Stats{Additions: 3, Deletions: 0, Commits: 1}, Weeks: weeks(app.Stats{Additions: 3, Deletions: 0, Commits: 1})},
+		{Name: "renovate[bot]", Bot: true, Stats: app.Stats{Additions: 1, Deletions: 1, Commits: 1}, Weeks: weeks(app.Stats{Additions: 1, Deletions: 1, Commits: 1})},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("CalcCommitContributions:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
+
 func TestNormaliseCalcContributionsOpts(t *testing.T) {
 	testDate, err := time.Parse("2006-01-02", "2018-06-16")
 	if err != nil {
@@ -275,3 +331,104 @@ func TestFilterContributors(t *testing.T) {
 		t.Errorf("FilterContributors:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
 	}
 }
+
+func TestMergeContributors(t *testing.T) {
+	input := []app.RepoContributors{
+		{
+			Repo: "test-owner/repo-a",
+			Contributors: []app.Contributor{
+				{
+					Name:  "Luke-Davies",
+					Stats: app.Stats{Additions: 10, Deletions: 5, Commits: 2},
+					Weeks: []app.WeekStats{week(6, 10, app.Stats{Additions: 4, Commits: 1}), week(6, 17, app.Stats{Additions: 6, Deletions: 5, Commits: 1})},
+				},
+				{Name: "Ron-Swanson", Stats: app.Stats{Additions: 1, Deletions: 1, Commits: 1}},
+			},
+		},
+		{
+			Repo: "test-owner/repo-b",
+			Contributors: []app.Contributor{
+				{Name: "Leslie-Knope", Stats: app.Stats{Additions: 7, Deletions: 0, Commits: 3}},
+				{
+					Name:  "Luke-Davies",
+					Stats: app.Stats{Additions: 20, Deletions: 10, Commits: 4},
+					Weeks: []app.WeekStats{week(6, 3, app.Stats{Additions: 5, Commits: 1}), week(6, 17, app.Stats{Additions: 15, Deletions: 10, Commits: 3})},
+				},
+			},
+		},
+	}
+
+	res := app.MergeContributors(input)
+	want := []app.Contributor{
+		{
+			Name:  "Luke-Davies",
+			Stats: app.Stats{Additions: 30, Deletions: 15, Commits: 6},
+			Repos: []app.RepoStats{
+				{
+					Repo:  "test-owner/repo-a",
+					Stats: app.Stats{Additions: 10, Deletions: 5, Commits: 2},
+					Weeks: []app.WeekStats{week(6, 10, app.Stats{Additions: 4, Commits: 1}), week(6, 17, app.Stats{Additions: 6, Deletions: 5, Commits: 1})},
+				},
+				{
+					Repo:  "test-owner/repo-b",
+					Stats: app.Stats{Additions: 20, Deletions: 10, Commits: 4},
+					Weeks: []app.WeekStats{week(6, 3, app.Stats{Additions: 5, Commits: 1}), week(6, 17, app.Stats{Additions: 15, Deletions: 10, Commits: 3})},
+				},
+			},
+			Weeks: []app.WeekStats{
+				week(6, 3, app.Stats{Additions: 5, Commits: 1}),
+				week(6, 10, app.Stats{Additions: 4, Commits: 1}),
+				week(6, 17, app.Stats{Additions: 21, Deletions: 15, Commits: 4}),
+			},
+		},
+		{
+			Name:  "Ron-Swanson",
+			Stats: app.Stats{Additions: 1, Deletions: 1, Commits: 1},
+			Repos: []app.RepoStats{
+				{Repo: "test-owner/repo-a", Stats: app.Stats{Additions: 1, Deletions: 1, Commits: 1}},
+			},
+		},
+		{
+			Name:  "Leslie-Knope",
+			Stats: app.Stats{Additions: 7, Deletions: 0, Commits: 3},
+			Repos: []app.RepoStats{
+				{Repo: "test-owner/repo-b", Stats: app.Stats{Additions: 7, Deletions: 0, Commits: 3}},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("MergeContributors:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
+
+func TestDerivedMetrics(t *testing.T) {
+	cs := app.WithRepoTotal([]app.Contributor{
+		{Name: "Luke-Davies", Stats: app.Stats{Additions: 30, Deletions: 10, Commits: 4}},
+		{Name: "Ron-Swanson", Stats: app.Stats{Additions: 60, Deletions: 0, Commits: 12}},
+		{Name: "Tom-Haverford"},
+	})
+
+	ts := []struct {
+		Contributor          app.Contributor
+		ExpectLinesPerCommit float64
+		ExpectCommitShare    float64
+		ExpectLineShare      float64
+	}{
+		{Contributor: cs[0], ExpectLinesPerCommit: 10, ExpectCommitShare: 25, ExpectLineShare: 40},
+		{Contributor: cs[1], ExpectLinesPerCommit: 5, ExpectCommitShare: 75, ExpectLineShare: 60},
+		{Contributor: cs[2], ExpectLinesPerCommit: 0, ExpectCommitShare: 0, ExpectLineShare: 0},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Contributor.Name, func(t *testing.T) {
+			c := tc.Contributor
+			if c.RepoTotal != (app.Stats{Additions: 90, Deletions: 10, Commits: 16}) {
+				t.Errorf("WithRepoTotal: have %+v", c.RepoTotal)
+			}
+			if c.Stats.LinesPerCommit() != tc.ExpectLinesPerCommit || c.CommitShare() != tc.ExpectCommitShare || c.LineShare() != tc.ExpectLineShare {
+				t.Errorf("have %v, %v, %v want %v, %v, %v", c.Stats.LinesPerCommit(), c.CommitShare(), c.LineShare(), tc.ExpectLinesPerCommit, tc.ExpectCommitShare, tc.ExpectLineShare)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package app
+
+// Statuses of a Change
+const (
+	StatusActive   = "active"   // committed in both date ranges
+	StatusNew      = "new"      // only committed in the current date range i.e. newly active
+	StatusInactive = "inactive" // only committed in the previous date range i.e. no longer active
+	StatusIdle     = "idle"     // committed in neither, only listed with --all
+)
+
+// Comparison is our apps model of the output of the compare command:
+// the contributions in a previous date range against those in the current one.
+// - Changes: how each contributor in either report changed. See CompareReports for the order.
+type Comparison struct {
+	Previous Report
+	Current  Report
+	Changes  []Change
+}
+
+// Change is our apps model of how a contributor's stats changed between two date ranges
+// - Status: one of the Status constants
+type Change struct {
+	Name        string
+	DisplayName string
+	Team        string
+	Bot         bool
+	Previous    Stats
+	Current     Stats
+	Status      string
+}
+
+// Delta returns the change in each stat i.e. the current stats minus the previous ones
+func (c Change) Delta() Stats {
+	return c.Current.Sub(c.Previous)
+}
+
+// PercentChange returns the change from previous to current as a percentage of previous
+// e.g. 50 for 10 to 15 or -100 for 10 to 0.
+// The change from zero is undefined, so the second result is false when previous is zero.
+func PercentChange(previous, current int) (float64, bool) {
+	if previous == 0 {
+		return 0, false
+	}
+	return float64(current-previous) * 100 / float64(previous), true
+}
+
+// CompareReports returns the change of every contributor in either report, matched by name.
+// Changes are in the order of the current report's contributors, followed by those only in the previous report
+// in its order, so that the contributors who are no longer active are listed last.
+func CompareReports(previous, current Report) Comparison {
+	res := Comparison{Previous: previous, Current: current, Changes: make([]Change, 0)}
+
+	prev := make(map[string]Contributor, len(previous.Contributors))
+	for _, c := range previous.Contributors {
+		prev[c.Name] = c
+	}
+
+	seen := make(map[string]bool, len(current.Contributors))
+	for _, c := range current.Contributors {
+		seen[c.Name] = true
+		p := prev[c.Name]
+		res.Changes = append(res.Changes, newChange(c, p.Stats, c.Stats))
+	}
+	for _, c := range previous.Contributors {
+		if !seen[c.Name] {
+			res.Changes = append(res.Changes, newChange(c, c.Stats, Stats{}))
+		}
+	}
+	return res
+}
+
+func newChange(c Contributor, previous, current Stats) Change {
+	res := Change{Name: c.Name, DisplayName: c.DisplayName, Team: c.Team, Bot: c.Bot, Previous: previous, Current: current}
+	switch {
+	case previous.Commits > 0 && current.Commits > 0:
+		res.Status = StatusActive
+	case current.Commits > 0:
+		res.Status = StatusNew
+	case previous.Commits > 0:
+		res.Status = StatusInactive
+	default:
+		res.Status = StatusIdle
+	}
+	return res
+}
+
+// PreviousRange returns the date range to compare the given one against, which ends where it starts.
+// If the range is a whole number of months, e.g. a quarter, the previous range is the same number of months.
+// Otherwise it's the same length.
+func PreviousRange(options CalcContrbutionsOpts) CalcContrbutionsOpts {
+	for months := 1; !options.From.AddDate(0, months, 0).After(options.To); months++ {
+		if options.From.AddDate(0, months, 0).Equal(options.To) {
+			return CalcContrbutionsOpts{From: options.From.AddDate(0, -months, 0), To: options.From}
+		}
+	}
+	return CalcContrbutionsOpts{From: options.From.Add(-options.To.Sub(options.From)), To: options.From}
+}
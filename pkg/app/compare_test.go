@@ -0,0 +1,95 @@
+package app_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestCompareReports(t *testing.T) {
+	previous := app.Report{Contributors: []app.Contributor{
+		{Name: "Ron-Swanson", Stats: app.Stats{Commits: 4, Additions: 40, Deletions: 10}},
+		{Name: "Luke-Davies", Stats: app.Stats{Commits: 10, Additions: 100, Deletions: 0}},
+		{Name: "Tom-Haverford", Stats: app.Stats{}},
+	}}
+	current := app.Report{Contributors: []app.Contributor{
+		{Name: "Luke-Davies", Stats: app.Stats{Commits: 15, Additions: 50, Deletions: 5}},
+		{Name: "Leslie-Knope", DisplayName: "Leslie Knope", Team: "parks", Stats: app.Stats{Commits: 2, Additions: 20, Deletions: 2}},
+		{Name: "Tom-Haverford", Stats: app.Stats{}},
+	}}
+
+	res := app.CompareReports(previous, current)
+	want := []app.Change{
+		{Name: "Luke-Davies", Previous: app.Stats{Commits: 10, Additions: 100}, Current: app.Stats{Commits: 15, Additions: 50, Deletions: 5}, Status: app.StatusActive},
+		{Name: "Leslie-Knope", DisplayName: "Leslie Knope", Team: "parks", Current: app.Stats{Commits: 2, Additions: 20, Deletions: 2}, Status: app.StatusNew},
+		{Name: "Tom-Haverford", Status: app.StatusIdle},
+		{Name: "Ron-Swanson", Previous: app.Stats{Commits: 4, Additions: 40, Deletions: 10}, Status: app.StatusInactive},
+	}
+	if !reflect.DeepEqual(res.Changes, want) {
+		t.Errorf("CompareReports:\n\nhave result:\n%+v\n\nwant result:\n%+v", res.Changes, want)
+	}
+
+	if delta := res.Changes[0].Delta(); delta != (app.Stats{Commits: 5, Additions: -50, Deletions: 5}) {
+		t.Errorf("Delta: have %+v want %+v", delta, app.Stats{Commits: 5, Additions: -50, Deletions: 5})
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	ts := []struct {
+		Previous int
+		Current  int
+		Expect   float64
+		ExpectOK bool
+	}{
+		{Previous: 10, Current: 15, Expect: 50, ExpectOK: true},
+		{Previous: 8, Current: 6, Expect: -25, ExpectOK: true},
+		{Previous: 10, Current: 0, Expect: -100, ExpectOK: true},
+		{Previous: 0, Current: 5, Expect: 0, ExpectOK: false},
+	}
+
+	for _, tc := range ts {
+		res, ok := app.PercentChange(tc.Previous, tc.Current)
+		if res != tc.Expect || ok != tc.ExpectOK {
+			t.Errorf("PercentChange(%d, %d): have %v, %t want %v, %t", tc.Previous, tc.Current, res, ok, tc.Expect, tc.ExpectOK)
+		}
+	}
+}
+
+func TestPreviousRange(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	ts := []struct {
+		Name   string
+		Range  app.CalcContrbutionsOpts
+		Expect app.CalcContrbutionsOpts
+	}{
+		{
+			Name:   "Quarter",
+			Range:  app.CalcContrbutionsOpts{From: date(2018, 7, 1), To: date(2018, 10, 1)},
+			Expect: app.CalcContrbutionsOpts{From: date(2018, 4, 1), To: date(2018, 7, 1)},
+		},
+		{
+			Name:   "Months",
+			Range:  app.CalcContrbutionsOpts{From: date(2018, 1, 15), To: date(2018, 3, 15)},
+			Expect: app.CalcContrbutionsOpts{From: date(2017, 11, 15), To: date(2018, 1, 15)},
+		},
+		{
+			Name:   "Days",
+			Range:  app.CalcContrbutionsOpts{From: date(2018, 6, 3), To: date(2018, 6, 17)},
+			Expect: app.CalcContrbutionsOpts{From: date(2018, 5, 20), To: date(2018, 6, 3)},
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			res := app.PreviousRange(tc.Range)
+			if !res.From.Equal(tc.Expect.From) || !res.To.Equal(tc.Expect.To) {
+				t.Errorf("PreviousRange: have %s - %s want %s - %s", res.From, res.To, tc.Expect.From, tc.Expect.To)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package app
+
+import "strings"
+
+// Exclusions describes which contributors to leave out of a report
+// - Bots: leave out bot accounts, see Contributor.Bot.
+// - Include: when not empty, only these logins are kept. They're kept even if they're bots.
+// - Exclude: leave out these logins, even if they're also in Include.
+// Logins are matched case insensitively, like GitHub does.
+type Exclusions struct {
+	Bots    bool
+	Include []string
+	Exclude []string
+}
+
+// Excluded sums up the contributors left out by ExcludeContributors
+// Bots is how many of the Contributors are bots.
+type Excluded struct {
+	Contributors int
+	Bots         int
+	Stats        Stats
+}
+
+// ExcludeContributors returns a new slice of the contributors kept by e, in order,
+// and a summary of the ones left out.
+func ExcludeContributors(cs []Contributor, e Exclusions) ([]Contributor, Excluded) {
+	include := loginSet(e.Include)
+	exclude := loginSet(e.Exclude)
+
+	res := make([]Contributor, 0)
+	var excluded Excluded
+	for _, c := range cs {
+		login := strings.ToLower(c.Name)
+		listed := include[login]
+		keep := !exclude[login] &&
+			(len(include) == 0 || listed) &&
+			(!e.Bots || !c.Bot || listed)
+		if keep {
+			res = append(res, c)
+			continue
+		}
+		excluded.Contributors++
+		if c.Bot {
+			excluded.Bots++
+		}
+		excluded.Stats = excluded.Stats.Add(c.Stats)
+	}
+	return res, excluded
+}
+
+func loginSet(logins []string) map[string]bool {
+	set := make(map[string]bool, len(logins))
+	for _, l := range logins {
+		set[strings.ToLower(l)] = true
+	}
+	return set
+}
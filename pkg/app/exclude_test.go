@@ -0,0 +1,71 @@
+package app_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestExcludeContributors(t *testing.T) {
+	input := []app.Contributor{
+		{Name: "Luke-Davies", Stats: app.Stats{Additions: 30, Deletions: 5, Commits: 2}},
+		{Name: "dependabot[bot]", Bot: true, Stats: app.Stats{Additions: 10, Deletions: 10, Commits: 20}},
+		{Name: "ci-user", Stats: app.Stats{Additions: 5, Deletions: 0, Commits: 5}},
+		{Name: "renovate[bot]", Bot: true, Stats: app.Stats{Additions: 1, Deletions: 1, Commits: 1}},
+	}
+
+	ts := []struct {
+		Name           string
+		Exclusions     app.Exclusions
+		Expect         []string
+		ExpectExcluded app.Excluded
+	}{
+		{
+			Name:   "None",
+			Expect: []string{"Luke-Davies", "dependabot[bot]", "ci-user", "renovate[bot]"},
+		},
+		{
+			Name:           "Bots",
+			Exclusions:     app.Exclusions{Bots: true},
+			Expect:         []string{"Luke-Davies", "ci-user"},
+			ExpectExcluded: app.Excluded{Contributors: 2, Bots: 2, Stats: app.Stats{Additions: 11, Deletions: 11, Commits: 21}},
+		},
+		{
+			// logins are case insensitive
+			Name:           "Bots And Exclude List",
+			Exclusions:     app.Exclusions{Bots: true, Exclude: []string{"CI-User"}},
+			Expect:         []string{"Luke-Davies"},
+			ExpectExcluded: app.Excluded{Contributors: 3, Bots: 2, Stats: app.Stats{Additions: 16, Deletions: 11, Commits: 26}},
+		},
+		{
+			// listed bots are kept
+			Name:           "Include List",
+			Exclusions:     app.Exclusions{Bots: true, Include: []string{"luke-davies", "renovate[bot]"}},
+			Expect:         []string{"Luke-Davies", "renovate[bot]"},
+			ExpectExcluded: app.Excluded{Contributors: 2, Bots: 1, Stats: app.Stats{Additions: 15, Deletions: 10, Commits: 25}},
+		},
+		{
+			Name:           "Exclude Beats Include",
+			Exclusions:     app.Exclusions{Include: []string{"Luke-Davies", "ci-user"}, Exclude: []string{"ci-user"}},
+			Expect:         []string{"Luke-Davies"},
+			ExpectExcluded: app.Excluded{Contributors: 3, Bots: 2, Stats: app.Stats{Additions: 16, Deletions: 11, Commits: 26}},
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			res, excluded := app.ExcludeContributors(input, tc.Exclusions)
+			var names []string
+			for _, c := range res {
+				names = append(names, c.Name)
+			}
+			if !reflect.DeepEqual(names, tc.Expect) {
+				t.Errorf("ExcludeContributors: have %v want %v", names, tc.Expect)
+			}
+			if excluded != tc.ExpectExcluded {
+				t.Errorf("ExcludeContributors: have excluded %+v want %+v", excluded, tc.ExpectExcluded)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+)
+
+// WeekActivity is our apps model of the repo-wide activity for a week
+// - Days: commits per day of the week, starting on Sunday
+// - Stats: totals for the week
+type WeekActivity struct {
+	WeekBeginning time.Time
+	Days          [7]int
+	Stats         Stats
+}
+
+func (w WeekActivity) String() string {
+	return fmt.Sprintf("Week beginning: %s\t %s Days (Sun-Sat): %v\t\n", w.WeekBeginning.UTC().Format("2006-01-02"), w.Stats, w.Days)
+}
+
+// CalcWeeklyActivity combines the commit activity and code frequency of a repo into weekly activity
+// for the given options. Weeks are returned oldest first.
+// Activity from several repos can be passed together, in which case it is summed per week.
+//
+// As with CalcContributions, the date range can only be applied against week beginning.
+// NB: GitHub only sends commit activity for the last year so older weeks will only have additions and deletions.
+func CalcWeeklyActivity(activity []github.CommitActivity, frequency []github.CodeFrequency, options CalcContrbutionsOpts) []WeekActivity {
+	weeks := make(map[int64]*WeekActivity)
+	week := func(wb int64) *WeekActivity {
+		if _, ok := weeks[wb]; !ok {
+			weeks[wb] = &WeekActivity{WeekBeginning: time.Unix(wb, 0)}
+		}
+		return weeks[wb]
+	}
+
+	for _, a := range activity {
+		if !inRange(time.Unix(a.WeekBeginning, 0), options) {
+			continue
+		}
+		w := week(a.WeekBeginning)
+		for i := 0; i < len(a.Days) && i < len(w.Days); i++ {
+			w.Days[i] += a.Days[i]
+		}
+		w.Stats.Commits += a.Total
+	}
+
+	for _, f := range frequency {
+		if !inRange(time.Unix(f.WeekBeginning, 0), options) {
+			continue
+		}
+		w := week(f.WeekBeginning)
+		w.Stats.Additions += f.Additions
+		w.Stats.Deletions += f.Deletions
+	}
+
+	res := make([]WeekActivity, 0, len(weeks))
+	for _, w := range weeks {
+		res = append(res, *w)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].WeekBeginning.Before(res[j].WeekBeginning) })
+	return res
+}
+
+// ParticipationWeek is our apps model of the owner vs. non-owner commits for a week
+type ParticipationWeek struct {
+	WeekBeginning time.Time
+	Owner         int
+	NonOwner      int
+}
+
+func (w ParticipationWeek) String() string {
+	return fmt.Sprintf("Week beginning: %s\t Owner: %d\t Non-owner: %d\t\n", w.WeekBeginning.UTC().Format("2006-01-02"), w.Owner, w.NonOwner)
+}
+
+// Participation is our apps model of owner vs. non-owner participation in a repo.
+// Weeks are oldest first and Owner & NonOwner are the totals over those weeks.
+type Participation struct {
+	Weeks    []ParticipationWeek
+	Owner    int
+	NonOwner int
+}
+
+// OwnerShare returns the percentage of commits made by the owner. Zero if there are no commits.
+func (p Participation) OwnerShare() float64 {
+	if p.Owner+p.NonOwner == 0 {
+		return 0
+	}
+	return float64(p.Owner) / float64(p.Owner+p.NonOwner) * 100
+}
+
+// CalcParticipation calculates owner vs. non-owner participation for the given options.
+//
+// GitHub doesn't send dates with participation, just the last 52 weeks with the current week last,
+// so week beginnings are worked out from `now`. As with CalcContributions, the date range can only
+// be applied against week beginning.
+func CalcParticipation(participation github.Participation, options CalcContrbutionsOpts, now time.Time) Participation {
+	res := Participation{}
+	current := WeekBeginning(now)
+
+	for i, all := range participation.All {
+		wb := current.AddDate(0, 0, -7*(len(participation.All)-1-i))
+		if !inRange(wb, options) {
+			continue
+		}
+
+		owner := 0
+		if i < len(participation.Owner) {
+			owner = participation.Owner[i]
+		}
+
+		res.Weeks = append(res.Weeks, ParticipationWeek{WeekBeginning: wb, Owner: owner, NonOwner: all - owner})
+		res.Owner += owner
+		res.NonOwner += all - owner
+	}
+	return res
+}
+
+// MergeParticipation sums the participation of several repos.
+// GitHub sends the last 52 weeks with the current week last so the weeks are aligned from the end.
+func MergeParticipation(participations []github.Participation) github.Participation {
+	n := 0
+	for _, p := range participations {
+		if len(p.All) > n {
+			n = len(p.All)
+		}
+	}
+
+	res := github.Participation{All: make([]int, n), Owner: make([]int, n)}
+	for _, p := range participations {
+		for i := range p.All {
+			res.All[n-len(p.All)+i] += p.All[i]
+		}
+		for i := range p.Owner {
+			res.Owner[n-len(p.Owner)+i] += p.Owner[i]
+		}
+	}
+	return res
+}
+
+// PunchCard is our apps model of a punch card i.e. a heatmap of commits.
+// Indexed by day of the week (Sunday is 0) then hour of the day.
+type PunchCard [7][24]int
+
+// Max returns the highest number of commits in any hour
+func (pc PunchCard) Max() int {
+	max := 0
+	for _, day := range pc {
+		for _, c := range day {
+			if c > max {
+				max = c
+			}
+		}
+	}
+	return max
+}
+
+// CalcPunchCard converts GitHub's punch card into a heatmap.
+// NB: GitHub's punch card covers the whole history of the repo so there is no date range.
+func CalcPunchCard(punchCard []github.PunchCard) PunchCard {
+	var res PunchCard
+	for _, pc := range punchCard {
+		if pc.Day < 0 || pc.Day > 6 || pc.Hour < 0 || pc.Hour > 23 {
+			continue
+		}
+		res[pc.Day][pc.Hour] += pc.Commits
+	}
+	return res
+}
+
+// WeekBeginning returns the start (Sunday 00:00 UTC) of the GitHub stats week containing t
+func WeekBeginning(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
+
+// inRange returns true if t is in the range of the given options (`from` inclusive, `to` exclusive)
+func inRange(t time.Time, options CalcContrbutionsOpts) bool {
+	return (options.From.Equal(t) || t.After(options.From)) && t.Before(options.To)
+}
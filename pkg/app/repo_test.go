@@ -0,0 +1,139 @@
+package app_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+)
+
+func TestCalcWeeklyActivity(t *testing.T) {
+	activity := []github.CommitActivity{
+		{WeekBeginning: 1529193600, Total: 5, Days: []int{0, 1, 2, 2, 0, 0, 0}},
+		{WeekBeginning: 1528588800, Total: 3, Days: []int{0, 0, 0, 0, 3, 0, 0}},
+	}
+	frequency := []github.CodeFrequency{
+		{WeekBeginning: 1528588800, Additions: 10, Deletions: 4},
+		{WeekBeginning: 1529193600, Additions: 20, Deletions: 8},
+		{WeekBeginning: 1527984000, Additions: 1, Deletions: 1},
+	}
+
+	res := app.CalcWeeklyActivity(activity, frequency, app.CalcContrbutionsOpts{From: time.Unix(1528588800, 0), To: time.Now()})
+	want := []app.WeekActivity{
+		{
+			WeekBeginning: time.Unix(1528588800, 0),
+			Days:          [7]int{0, 0, 0, 0, 3, 0, 0},
+			Stats:         app.Stats{Commits: 3, Additions: 10, Deletions: 4},
+		},
+		{
+			WeekBeginning: time.Unix(1529193600, 0),
+			Days:          [7]int{0, 1, 2, 2, 0, 0, 0},
+			Stats:         app.Stats{Commits: 5, Additions: 20, Deletions: 8},
+		},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("CalcWeeklyActivity:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
+
+func TestCalcParticipation(t *testing.T) {
+	// a wednesday, so the current week began on sunday 2018-06-24
+	now := time.Date(2018, 6, 27, 15, 0, 0, 0, time.UTC)
+	current := time.Date(2018, 6, 24, 0, 0, 0, 0, time.UTC)
+	participation := github.Participation{All: []int{5, 10, 20}, Owner: []int{1, 2, 3}}
+
+	ts := []struct {
+		Name      string
+		Options   app.CalcContrbutionsOpts
+		ExpectRes app.Participation
+	}{
+		{
+			Name:    "Full Range",
+			Options: app.CalcContrbutionsOpts{To: now},
+			ExpectRes: app.Participation{
+				Weeks: []app.ParticipationWeek{
+					{WeekBeginning: current.AddDate(0, 0, -14), Owner: 1, NonOwner: 4},
+					{WeekBeginning: current.AddDate(0, 0, -7), Owner: 2, NonOwner: 8},
+					{WeekBeginning: current, Owner: 3, NonOwner: 17},
+				},
+				Owner:    6,
+				NonOwner: 29,
+			},
+		},
+		{
+			Name:    "Last Week",
+			Options: app.CalcContrbutionsOpts{From: current.AddDate(0, 0, -7), To: current},
+			ExpectRes: app.Participation{
+				Weeks: []app.ParticipationWeek{
+					{WeekBeginning: current.AddDate(0, 0, -7), Owner: 2, NonOwner: 8},
+				},
+				Owner:    2,
+				NonOwner: 8,
+			},
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			res := app.CalcParticipation(participation, tc.Options, now)
+			if !reflect.DeepEqual(res, tc.ExpectRes) {
+				t.Errorf("CalcParticipation:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, tc.ExpectRes)
+			}
+		})
+	}
+
+	if share := app.CalcParticipation(participation, ts[1].Options, now).OwnerShare(); share != 20 {
+		t.Errorf("OwnerShare: have %f want 20", share)
+	}
+}
+
+func TestMergeParticipation(t *testing.T) {
+	res := app.MergeParticipation([]github.Participation{
+		{All: []int{5, 10, 20}, Owner: []int{1, 2, 3}},
+		{All: []int{1, 1}, Owner: []int{0, 1}},
+	})
+	want := github.Participation{All: []int{5, 11, 21}, Owner: []int{1, 2, 4}}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("MergeParticipation:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
+
+func TestCalcPunchCard(t *testing.T) {
+	res := app.CalcPunchCard([]github.PunchCard{
+		{Day: 0, Hour: 0, Commits: 5},
+		{Day: 3, Hour: 14, Commits: 43},
+		{Day: 7, Hour: 0, Commits: 1}, // out of range, ignored
+	})
+
+	var want app.PunchCard
+	want[0][0] = 5
+	want[3][14] = 43
+
+	if res != want {
+		t.Errorf("CalcPunchCard:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+	if res.Max() != 43 {
+		t.Errorf("PunchCard.Max: have %d want 43", res.Max())
+	}
+}
+
+func TestWeekBeginning(t *testing.T) {
+	ts := []struct {
+		Input  time.Time
+		Expect time.Time
+	}{
+		{Input: time.Date(2018, 6, 27, 15, 0, 0, 0, time.UTC), Expect: time.Date(2018, 6, 24, 0, 0, 0, 0, time.UTC)},
+		{Input: time.Date(2018, 6, 24, 0, 0, 0, 0, time.UTC), Expect: time.Date(2018, 6, 24, 0, 0, 0, 0, time.UTC)},
+		{Input: time.Date(2018, 6, 23, 23, 59, 0, 0, time.UTC), Expect: time.Date(2018, 6, 17, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range ts {
+		if res := app.WeekBeginning(tc.Input); !res.Equal(tc.Expect) {
+			t.Errorf("WeekBeginning(%s): have %s want %s", tc.Input, res, tc.Expect)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import "time"
+
+// Report is our apps model of the output of the contributors command.
+// It's what every output format is rendered from.
+// - Repos: the repositories (owner/name) the stats cover
+// - From: the start of the date range. Zero if the range has no lower bound.
+// - To: the end of the date range
+// - GeneratedAt: when the stats were calculated
+// - Excluded: the contributors left out by ExcludeContributors, if any
+// - GroupBy: what each of the Contributors is, GroupByContributor or GroupByTeam. Empty means GroupByContributor.
+// - Interval: the interval to break the Contributors down by, see Series. Empty for their totals.
+type Report struct {
+	Repos        []string
+	From         time.Time
+	To           time.Time
+	GeneratedAt  time.Time
+	Contributors []Contributor
+	Excluded     Excluded
+	GroupBy      string
+	Interval     string
+}
+
+// Total returns the sum of the stats of all the contributors
+func (r Report) Total() Stats {
+	var res Stats
+	for _, c := range r.Contributors {
+		res = res.Add(c.Stats)
+	}
+	return res
+}
+
+// Weeks returns the beginning of every week of the report's date range, in order.
+// The range is applied to week beginnings like CalcContributions does, so it starts at the first week beginning
+// on or after From, unless there are contributions in the week From is in (only with CalcCommitContributions).
+// If the range has no lower bound, it starts at the earliest week with contributions.
+func (r Report) Weeks() []time.Time {
+	var first time.Time
+	if !r.From.IsZero() {
+		first = WeekBeginning(r.From)
+		if first.Before(r.From) {
+			first = first.AddDate(0, 0, 7)
+		}
+	}
+	for _, c := range r.Contributors {
+		if len(c.Weeks) == 0 {
+			continue
+		}
+		wb := c.Weeks[0].WeekBeginning
+		if (first.IsZero() || wb.Before(first)) && (r.From.IsZero() || !wb.Before(WeekBeginning(r.From))) {
+			first = wb
+		}
+	}
+	if first.IsZero() {
+		return nil
+	}
+
+	// `to` is exclusive
+	last := WeekBeginning(r.To.Add(-time.Nanosecond))
+
+	var res []time.Time
+	for wb := first; !wb.After(last); wb = wb.AddDate(0, 0, 7) {
+		res = append(res, wb)
+	}
+	return res
+}
+
+// WeeklyStats returns the contributor's stats for each of the given weeks (see Report.Weeks).
+// Weeks without contributions are zero.
+func (c Contributor) WeeklyStats(weeks []time.Time) []Stats {
+	res := make([]Stats, len(weeks))
+	byWeek := make(map[int64]Stats, len(c.Weeks))
+	for _, w := range c.Weeks {
+		byWeek[w.WeekBeginning.Unix()] = w.Stats
+	}
+	for i, wb := range weeks {
+		res[i] = byWeek[wb.Unix()]
+	}
+	return res
+}
@@ -0,0 +1,90 @@
+package app_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestReportTotal(t *testing.T) {
+	r := app.Report{
+		Contributors: []app.Contributor{
+			{Name: "Luke-Davies", Stats: app.Stats{Additions: 10, Deletions: 5, Commits: 2}},
+			{Name: "Ron-Swanson", Stats: app.Stats{Additions: 1, Deletions: 1, Commits: 1}},
+		},
+	}
+
+	want := app.Stats{Additions: 11, Deletions: 6, Commits: 3}
+	if res := r.Total(); res != want {
+		t.Errorf("Total:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
+
+func TestReportWeeks(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2018, 6, d, 0, 0, 0, 0, time.UTC) }
+	contributors := []app.Contributor{
+		{Name: "Luke-Davies", Weeks: []app.WeekStats{{WeekBeginning: day(10)}, {WeekBeginning: day(17)}}},
+		{Name: "Ron-Swanson", Weeks: []app.WeekStats{{WeekBeginning: day(3)}}},
+	}
+
+	ts := []struct {
+		Name      string
+		Report    app.Report
+		ExpectRes []time.Time
+	}{
+		{
+			// the week of 2018-06-03 is before the range
+			Name:      "Range",
+			Report:    app.Report{From: day(5), To: day(24), Contributors: contributors[:1]},
+			ExpectRes: []time.Time{day(10), day(17)},
+		},
+		{
+			// with --precision day, commits after From can be in the week it's in
+			Name:      "From Mid Week With Contributions",
+			Report:    app.Report{From: day(5), To: day(24), Contributors: contributors},
+			ExpectRes: []time.Time{day(3), day(10), day(17)},
+		},
+		{
+			Name:      "To Mid Week",
+			Report:    app.Report{From: day(10), To: day(25), Contributors: contributors},
+			ExpectRes: []time.Time{day(10), day(17), day(24)},
+		},
+		{
+			Name:      "No Lower Bound",
+			Report:    app.Report{To: day(18), Contributors: contributors},
+			ExpectRes: []time.Time{day(3), day(10), day(17)},
+		},
+		{
+			Name:   "No Lower Bound Or Contributions",
+			Report: app.Report{To: day(18)},
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			res := tc.Report.Weeks()
+			if !reflect.DeepEqual(res, tc.ExpectRes) {
+				t.Errorf("Weeks:\n\nhave result:\n%v\n\nwant result:\n%v", res, tc.ExpectRes)
+			}
+		})
+	}
+}
+
+func TestContributorWeeklyStats(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2018, 6, d, 0, 0, 0, 0, time.UTC) }
+	c := app.Contributor{
+		Name: "Luke-Davies",
+		Weeks: []app.WeekStats{
+			{WeekBeginning: day(3), Stats: app.Stats{Commits: 1}},
+			{WeekBeginning: day(17), Stats: app.Stats{Commits: 2}},
+		},
+	}
+
+	res := c.WeeklyStats([]time.Time{day(10), day(17), day(24)})
+	want := []app.Stats{{}, {Commits: 2}, {}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("WeeklyStats:\n\nhave result:\n%v\n\nwant result:\n%v", res, want)
+	}
+}
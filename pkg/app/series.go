@@ -0,0 +1,114 @@
+package app
+
+import (
+	"fmt"
+	"time"
+)
+
+// Intervals of a Series
+const (
+	IntervalWeek    = "week"
+	IntervalMonth   = "month"
+	IntervalQuarter = "quarter"
+	IntervalYear    = "year"
+)
+
+// Intervals lists the intervals, shortest first
+var Intervals = []string{IntervalWeek, IntervalMonth, IntervalQuarter, IntervalYear}
+
+// Series is our apps model of a contributor's stats per period of an interval
+// - Periods: the start of each period, in order
+// - Stats: the stats of each period. Periods without contributions are zero.
+type Series struct {
+	Name     string
+	Interval string
+	Periods  []time.Time
+	Stats    []Stats
+}
+
+// PeriodStart returns the start of the period of the interval that the week beginning wb is in.
+//
+// GitHub only has stats per week, so a week is assigned to a period as a whole:
+// a week is in the month, quarter or year its week beginning (a Sunday) is in.
+// e.g. the week beginning Sunday 2018-09-30 is in September and Q3 even though the rest of it is in October.
+// This matches how --from and --to are applied to weeks.
+func PeriodStart(interval string, wb time.Time) time.Time {
+	wb = wb.UTC()
+	switch interval {
+	case IntervalMonth:
+		return time.Date(wb.Year(), wb.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case IntervalQuarter:
+		return time.Date(wb.Year(), wb.Month()-(wb.Month()-1)%3, 1, 0, 0, 0, 0, time.UTC)
+	case IntervalYear:
+		return time.Date(wb.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return WeekBeginning(wb)
+	}
+}
+
+// nextPeriod returns the start of the period after the one starting at start
+func nextPeriod(interval string, start time.Time) time.Time {
+	switch interval {
+	case IntervalMonth:
+		return start.AddDate(0, 1, 0)
+	case IntervalQuarter:
+		return start.AddDate(0, 3, 0)
+	case IntervalYear:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 0, 7)
+	}
+}
+
+// PeriodLabel returns the short name of the period of the interval starting at start
+// e.g. 2018-06-03 (week), 2018-06 (month), 2018-Q2 (quarter) or 2018 (year)
+func PeriodLabel(interval string, start time.Time) string {
+	switch interval {
+	case IntervalMonth:
+		return start.Format("2006-01")
+	case IntervalQuarter:
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	case IntervalYear:
+		return start.Format("2006")
+	default:
+		return start.Format("2006-01-02")
+	}
+}
+
+// Periods returns the start of every period of the interval that the weeks of the report's
+// date range (see Weeks) are in, in order.
+func (r Report) Periods(interval string) []time.Time {
+	weeks := r.Weeks()
+	if len(weeks) == 0 {
+		return nil
+	}
+
+	last := PeriodStart(interval, weeks[len(weeks)-1])
+	var res []time.Time
+	for p := PeriodStart(interval, weeks[0]); !p.After(last); p = nextPeriod(interval, p) {
+		res = append(res, p)
+	}
+	return res
+}
+
+// Series returns the stats of each of the report's contributors per period of the interval,
+// in the order of the contributors. Every series has the same periods, see Periods.
+func (r Report) Series(interval string) []Series {
+	periods := r.Periods(interval)
+	index := make(map[int64]int, len(periods))
+	for i, p := range periods {
+		index[p.Unix()] = i
+	}
+
+	res := make([]Series, len(r.Contributors))
+	for i, c := range r.Contributors {
+		s := Series{Name: c.Name, Interval: interval, Periods: periods, Stats: make([]Stats, len(periods))}
+		for _, w := range c.Weeks {
+			if j, ok := index[PeriodStart(interval, w.WeekBeginning).Unix()]; ok {
+				s.Stats[j] = s.Stats[j].Add(w.Stats)
+			}
+		}
+		res[i] = s
+	}
+	return res
+}
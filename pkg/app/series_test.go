@@ -0,0 +1,81 @@
+package app_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestPeriodStartAndLabel(t *testing.T) {
+	// the week beginning Sunday 2018-09-30 is mostly in October
+	wb := time.Date(2018, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	ts := []struct {
+		Interval    string
+		ExpectStart time.Time
+		ExpectLabel string
+	}{
+		{Interval: app.IntervalWeek, ExpectStart: wb, ExpectLabel: "2018-09-30"},
+		{Interval: app.IntervalMonth, ExpectStart: time.Date(2018, 9, 1, 0, 0, 0, 0, time.UTC), ExpectLabel: "2018-09"},
+		{Interval: app.IntervalQuarter, ExpectStart: time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC), ExpectLabel: "2018-Q3"},
+		{Interval: app.IntervalYear, ExpectStart: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), ExpectLabel: "2018"},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Interval, func(t *testing.T) {
+			start := app.PeriodStart(tc.Interval, wb)
+			if !start.Equal(tc.ExpectStart) {
+				t.Errorf("PeriodStart: have %s want %s", start, tc.ExpectStart)
+			}
+			if label := app.PeriodLabel(tc.Interval, start); label != tc.ExpectLabel {
+				t.Errorf("PeriodLabel: have %s want %s", label, tc.ExpectLabel)
+			}
+		})
+	}
+}
+
+func TestReportSeries(t *testing.T) {
+	month := func(m time.Month) time.Time {
+		return time.Date(2018, m, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	r := app.Report{
+		From: time.Date(2018, 7, 29, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2018, 10, 7, 0, 0, 0, 0, time.UTC),
+		Contributors: []app.Contributor{
+			{
+				Name: "Luke-Davies",
+				Weeks: []app.WeekStats{
+					week(7, 29, app.Stats{Commits: 1}),
+					week(8, 5, app.Stats{Commits: 2}),
+					week(8, 12, app.Stats{Commits: 4}),
+					week(9, 30, app.Stats{Commits: 8}),
+				},
+			},
+			{Name: "Ron-Swanson"},
+		},
+	}
+	periods := []time.Time{month(7), month(8), month(9)}
+
+	res := r.Series(app.IntervalMonth)
+	want := []app.Series{
+		{
+			Name:     "Luke-Davies",
+			Interval: app.IntervalMonth,
+			Periods:  periods,
+			Stats:    []app.Stats{{Commits: 1}, {Commits: 6}, {Commits: 8}},
+		},
+		{
+			Name:     "Ron-Swanson",
+			Interval: app.IntervalMonth,
+			Periods:  periods,
+			Stats:    []app.Stats{{}, {}, {}},
+		},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("Series:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Fields contributors can be sorted by
+const (
+	SortCommits        = "commits"
+	SortAdditions      = "additions"
+	SortDeletions      = "deletions"
+	SortNet            = "net"
+	SortChurn          = "churn"
+	SortLinesPerCommit = "lines_per_commit"
+	SortCommitShare    = "commit_share"
+	SortLineShare      = "line_share"
+	SortName           = "name"
+)
+
+// numericFields are the fields of a contributor's stats that can be sorted and filtered by,
+// in the order they're listed in errors
+var numericFields = []struct {
+	Name  string
+	Value func(s Stats) int
+}{
+	{SortCommits, func(s Stats) int { return s.Commits }},
+	{SortAdditions, func(s Stats) int { return s.Additions }},
+	{SortDeletions, func(s Stats) int { return s.Deletions }},
+	{SortNet, Stats.Net},
+	{SortChurn, Stats.Churn},
+}
+
+// Metric returns how to get the numeric field with the given name e.g. SortCommits from stats,
+// or nil if there isn't one
+func Metric(name string) func(s Stats) int {
+	for _, f := range numericFields {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+	return nil
+}
+
+// MetricNames returns the names of the numeric fields
+func MetricNames() []string {
+	var names []string
+	for _, f := range numericFields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// derivedFields are the fields of a contributor that aren't a whole number of its stats,
+// in the order they're listed in errors
+var derivedFields = []struct {
+	Name  string
+	Value func(c Contributor) float64
+}{
+	{SortLinesPerCommit, func(c Contributor) float64 { return c.Stats.LinesPerCommit() }},
+	{SortCommitShare, Contributor.CommitShare},
+	{SortLineShare, Contributor.LineShare},
+}
+
+// contributorField returns how to get the numeric field with the given name from a contributor:
+// a Metric or one of the derived fields. It returns nil if there isn't one.
+func contributorField(name string) func(c Contributor) float64 {
+	if value := Metric(name); value != nil {
+		return func(c Contributor) float64 { return float64(value(c.Stats)) }
+	}
+	for _, f := range derivedFields {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+	return nil
+}
+
+// contributorFieldNames returns the names of the numeric fields of a contributor
+func contributorFieldNames() []string {
+	names := MetricNames()
+	for _, f := range derivedFields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// sortFieldNames returns the names of the fields contributors can be sorted by
+func sortFieldNames() []string {
+	return append(contributorFieldNames(), SortName)
+}
+
+// SortKey is a field to sort contributors by and its direction
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSortKeys parses a comma separated list of fields to sort by e.g. "commits,-additions".
+// A leading "-" sorts the field in descending order.
+func ParseSortKeys(s string) ([]SortKey, error) {
+	var res []SortKey
+	for _, f := range strings.Split(s, ",") {
+		key := SortKey{Field: strings.TrimSpace(f)}
+		if strings.HasPrefix(key.Field, "-") {
+			key.Field, key.Desc = key.Field[1:], true
+		}
+		if sortLess(key.Field) == nil {
+			return nil, errors.Errorf("[ParseSortKeys] unknown sort field %q. Should be one of: %s", key.Field, strings.Join(sortFieldNames(), ", "))
+		}
+		res = append(res, key)
+	}
+	return res, nil
+}
+
+// sortLess returns how the field compares two contributors, or nil if it isn't a field
+func sortLess(field string) func(a, b Contributor) bool {
+	if field == SortName {
+		return func(a, b Contributor) bool { return a.Name < b.Name }
+	}
+	if value := contributorField(field); value != nil {
+		return func(a, b Contributor) bool { return value(a) < value(b) }
+	}
+	return nil
+}
+
+// SortContributors returns a new slice of Contributors sorted by each of the keys in turn.
+// Contributors that are equal on every key are sorted by name, so the order is the same
+// between runs whatever order GitHub returns them in. Unknown fields are ignored.
+func SortContributors(cs []Contributor, keys []SortKey) []Contributor {
+	res := append([]Contributor(nil), cs...)
+	keys = append(append([]SortKey(nil), keys...), SortKey{Field: SortName})
+
+	sort.SliceStable(res, func(i, j int) bool {
+		for _, key := range keys {
+			less := sortLess(key.Field)
+			if less == nil {
+				continue
+			}
+			a, b := res[i], res[j]
+			if key.Desc {
+				a, b = b, a
+			}
+			if less(a, b) {
+				return true
+			}
+			if less(b, a) {
+				return false
+			}
+		}
+		return false
+	})
+	return res
+}
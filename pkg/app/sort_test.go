@@ -0,0 +1,122 @@
+package app_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	ts := []struct {
+		Name      string
+		Input     string
+		ExpectRes []app.SortKey
+		ExpectErr error
+	}{
+		{
+			Name:      "Several",
+			Input:     "commits,-additions, name",
+			ExpectRes: []app.SortKey{{Field: app.SortCommits}, {Field: app.SortAdditions, Desc: true}, {Field: app.SortName}},
+		},
+		{
+			Name:      "Unknown",
+			Input:     "commits,-stars",
+			ExpectErr: fmt.Errorf(`[ParseSortKeys] unknown sort field "stars". Should be one of: commits, additions, deletions, net, churn, lines_per_commit, commit_share, line_share, name`),
+		},
+		{
+			Name:      "Empty Field",
+			Input:     "commits,",
+			ExpectErr: fmt.Errorf(`[ParseSortKeys] unknown sort field "". Should be one of: commits, additions, deletions, net, churn, lines_per_commit, commit_share, line_share, name`),
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			res, err := app.ParseSortKeys(tc.Input)
+			if tc.ExpectErr != nil {
+				if err == nil || err.Error() != tc.ExpectErr.Error() {
+					t.Fatalf("ParseSortKeys: Have `err`: %v want: %v", err, tc.ExpectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSortKeys: Unexpected Error: %v", err)
+			}
+			if !reflect.DeepEqual(res, tc.ExpectRes) {
+				t.Errorf("ParseSortKeys:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, tc.ExpectRes)
+			}
+		})
+	}
+}
+
+func TestSortContributors(t *testing.T) {
+	input := []app.Contributor{
+		{Name: "Ron-Swanson", Stats: app.Stats{Additions: 10, Deletions: 10, Commits: 2}},
+		{Name: "Luke-Davies", Stats: app.Stats{Additions: 30, Deletions: 5, Commits: 2}},
+		{Name: "Leslie-Knope", Stats: app.Stats{Additions: 5, Deletions: 0, Commits: 5}},
+		{Name: "April-Ludgate", Stats: app.Stats{Additions: 10, Deletions: 10, Commits: 2}},
+	}
+
+	ts := []struct {
+		Name   string
+		Keys   []app.SortKey
+		Expect []string
+	}{
+		{
+			// ties are broken by name
+			Name:   "No Keys",
+			Expect: []string{"April-Ludgate", "Leslie-Knope", "Luke-Davies", "Ron-Swanson"},
+		},
+		{
+			Name:   "Commits Desc Then Additions Desc",
+			Keys:   []app.SortKey{{Field: app.SortCommits, Desc: true}, {Field: app.SortAdditions, Desc: true}},
+			Expect: []string{"Leslie-Knope", "Luke-Davies", "April-Ludgate", "Ron-Swanson"},
+		},
+		{
+			Name:   "Net",
+			Keys:   []app.SortKey{{Field: app.SortNet}},
+			Expect: []string{"April-Ludgate", "Ron-Swanson", "Leslie-Knope", "Luke-Davies"},
+		},
+		{
+			Name:   "Churn Desc",
+			Keys:   []app.SortKey{{Field: app.SortChurn, Desc: true}},
+			Expect: []string{"Luke-Davies", "April-Ludgate", "Ron-Swanson", "Leslie-Knope"},
+		},
+		{
+			Name:   "Lines Per Commit Desc",
+			Keys:   []app.SortKey{{Field: app.SortLinesPerCommit, Desc: true}},
+			Expect: []string{"Luke-Davies", "April-Ludgate", "Ron-Swanson", "Leslie-Knope"},
+		},
+		{
+			Name:   "Name Desc",
+			Keys:   []app.SortKey{{Field: app.SortName, Desc: true}},
+			Expect: []string{"Ron-Swanson", "Luke-Davies", "Leslie-Knope", "April-Ludgate"},
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			res := app.SortContributors(input, tc.Keys)
+			var names []string
+			for _, c := range res {
+				names = append(names, c.Name)
+			}
+			if !reflect.DeepEqual(names, tc.Expect) {
+				t.Errorf("SortContributors: have %v want %v", names, tc.Expect)
+			}
+		})
+	}
+
+	if input[0].Name != "Ron-Swanson" {
+		t.Error("SortContributors: the input was modified")
+	}
+}
+
+func TestStatsNetAndChurn(t *testing.T) {
+	s := app.Stats{Additions: 5, Deletions: 8}
+	if s.Net() != -3 || s.Churn() != 13 {
+		t.Errorf("Stats: have Net %d and Churn %d want -3 and 13", s.Net(), s.Churn())
+	}
+}
@@ -0,0 +1,106 @@
+package app
+
+import "strings"
+
+// Groupings of the contributors in a report, see Report.GroupBy
+const (
+	GroupByContributor = "contributor"
+	GroupByTeam        = "team"
+)
+
+// NoTeam is the name of the team of the contributors that aren't in any team
+const NoTeam = "(no team)"
+
+// TeamMembers is our apps model of the members (logins) of a team
+type TeamMembers struct {
+	Team    string
+	Members []string
+}
+
+// GroupByTeams rolls the contributors up into a contributor per team, summing their stats per repo and per week.
+// Members holds the contributors of each team, in order.
+//
+// A contributor is in the teams that list their login (case insensitively) and in their own Team, if set
+// (see MergeAliases). Someone in several teams counts towards each of them, so the totals of the teams can add up
+// to more than the total of the contributors. Contributors that aren't in any team are grouped into NoTeam.
+//
+// Teams are returned in the order of teams, then the teams only known from Contributor.Team in the order they
+// first appear, then NoTeam. Teams without contributors are left out.
+func GroupByTeams(cs []Contributor, teams []TeamMembers) []Contributor {
+	// the teams of each login
+	memberOf := make(map[string][]string)
+	var order []string
+	seen := make(map[string]bool)
+	addTeam := func(team string) {
+		if !seen[team] {
+			seen[team] = true
+			order = append(order, team)
+		}
+	}
+	for _, t := range teams {
+		addTeam(t.Team)
+		for _, m := range t.Members {
+			login := strings.ToLower(m)
+			memberOf[login] = appendUnique(memberOf[login], t.Team)
+		}
+	}
+
+	grouped := make(map[string]*Contributor)
+	var noTeam []Contributor
+	for _, c := range cs {
+		in := append([]string(nil), memberOf[strings.ToLower(c.Name)]...)
+		if c.Team != "" {
+			in = appendUnique(in, c.Team)
+			addTeam(c.Team)
+		}
+		if len(in) == 0 {
+			noTeam = append(noTeam, c)
+			continue
+		}
+		for _, team := range in {
+			g, ok := grouped[team]
+			if !ok {
+				g = &Contributor{Name: team, Team: team}
+				grouped[team] = g
+			}
+			addMember(g, c)
+		}
+	}
+
+	res := make([]Contributor, 0, len(grouped)+1)
+	for _, team := range order {
+		if g, ok := grouped[team]; ok {
+			res = append(res, *g)
+		}
+	}
+	if len(noTeam) > 0 {
+		g := Contributor{Name: NoTeam}
+		for _, c := range noTeam {
+			addMember(&g, c)
+		}
+		res = append(res, g)
+	}
+	return res
+}
+
+// addMember adds the contributor's stats to the team's and adds them to its Members. Every member has the same RepoTotal.
+func addMember(team *Contributor, c Contributor) {
+	team.Stats = team.Stats.Add(c.Stats)
+	team.RepoTotal = c.RepoTotal
+	for _, rs := range c.Repos {
+		team.Repos = addRepo(team.Repos, rs)
+	}
+	for _, w := range c.Weeks {
+		team.Weeks = addWeek(team.Weeks, w)
+	}
+	team.Members = append(team.Members, c)
+}
+
+func appendUnique(ss []string, s string) []string {
+	for _, v := range ss {
+		if v == s {
+			return ss
+		}
+	}
+	return append(ss, s)
+}
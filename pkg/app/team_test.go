@@ -0,0 +1,61 @@
+package app_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestGroupByTeams(t *testing.T) {
+	luke := app.Contributor{
+		Name:  "Luke-Davies",
+		Stats: app.Stats{Additions: 10, Deletions: 5, Commits: 2},
+		Repos: []app.RepoStats{{Repo: "test-owner/repo-a", Stats: app.Stats{Additions: 10, Deletions: 5, Commits: 2}}},
+		Weeks: []app.WeekStats{week(6, 10, app.Stats{Additions: 10, Deletions: 5, Commits: 2})},
+	}
+	ron := app.Contributor{
+		Name:  "Ron-Swanson",
+		Stats: app.Stats{Additions: 1, Deletions: 1, Commits: 1},
+		Repos: []app.RepoStats{{Repo: "test-owner/repo-a", Stats: app.Stats{Additions: 1, Deletions: 1, Commits: 1}}},
+		Weeks: []app.WeekStats{week(6, 17, app.Stats{Additions: 1, Deletions: 1, Commits: 1})},
+	}
+	// only in a team from the alias file
+	leslie := app.Contributor{Name: "Leslie-Knope", Team: "parks", Stats: app.Stats{Additions: 7, Commits: 3}}
+	april := app.Contributor{Name: "April-Ludgate", Stats: app.Stats{Commits: 1}}
+
+	teams := []app.TeamMembers{
+		{Team: "empty", Members: []string{"Andy-Dwyer"}},
+		{Team: "backend", Members: []string{"luke-davies", "Ron-Swanson"}},
+		{Team: "reviewers", Members: []string{"Ron-Swanson"}},
+	}
+
+	res := app.GroupByTeams([]app.Contributor{april, leslie, luke, ron}, teams)
+	want := []app.Contributor{
+		{
+			Name:  "backend",
+			Team:  "backend",
+			Stats: app.Stats{Additions: 11, Deletions: 6, Commits: 3},
+			Repos: []app.RepoStats{{Repo: "test-owner/repo-a", Stats: app.Stats{Additions: 11, Deletions: 6, Commits: 3}}},
+			Weeks: []app.WeekStats{
+				week(6, 10, app.Stats{Additions: 10, Deletions: 5, Commits: 2}),
+				week(6, 17, app.Stats{Additions: 1, Deletions: 1, Commits: 1}),
+			},
+			Members: []app.Contributor{luke, ron},
+		},
+		{
+			Name:    "reviewers",
+			Team:    "reviewers",
+			Stats:   ron.Stats,
+			Repos:   []app.RepoStats{{Repo: "test-owner/repo-a", Stats: ron.Stats}},
+			Weeks:   ron.Weeks,
+			Members: []app.Contributor{ron},
+		},
+		{Name: "parks", Team: "parks", Stats: leslie.Stats, Members: []app.Contributor{leslie}},
+		{Name: app.NoTeam, Stats: april.Stats, Members: []app.Contributor{april}},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("GroupByTeams:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
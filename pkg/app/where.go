@@ -0,0 +1,299 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// CompileWhere compiles a filter expression into a predicate for FilterContributors.
+//
+// An expression compares the fields of a contributor against literals and combines the
+// comparisons with && (and), || (or), ! (not) and parentheses, e.g.
+//
+//	commits >= 5 && additions > 100 && login !~ "bot$"
+//
+// The numeric fields commits, additions, deletions, net, churn, lines_per_commit, commit_share and
+// line_share (percentages e.g. 12.5) compare against numbers with ==, !=, <, <=, > and >=.
+// The text fields login and name (the same thing) compare against double quoted strings
+// with == and != or match regular expressions with =~ and !~.
+// && binds tighter than ||.
+func CompileWhere(expr string) (func(Contributor) bool, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, errors.Wrap(err, "[CompileWhere] invalid expression")
+	}
+	p := parser{tokens: tokens}
+	f, err := p.or()
+	if err == nil && p.peek().kind != tokEOF {
+		err = p.errorf("expected && or || but found %s", p.peek())
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "[CompileWhere] invalid expression")
+	}
+	return f, nil
+}
+
+// textFields are the fields of a contributor that can be compared as text
+var textFields = []string{"login", "name"}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based column of the token in the expression
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// operators are the operators of the language, longest first so they lex greedily
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!"}
+
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	rs := []rune(expr)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "(", start + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")", start + 1})
+			i++
+		case r == '"':
+			for i++; i < len(rs) && rs[i] != '"'; i++ {
+				if rs[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(rs) {
+				return nil, errors.Errorf("unterminated string at column %d", start+1)
+			}
+			i++
+			s, err := strconv.Unquote(string(rs[start:i]))
+			if err != nil {
+				return nil, errors.Errorf("invalid string %s at column %d", string(rs[start:i]), start+1)
+			}
+			tokens = append(tokens, token{tokString, s, start + 1})
+		case unicode.IsDigit(r) || r == '-' && i+1 < len(rs) && unicode.IsDigit(rs[i+1]):
+			for i++; i < len(rs) && (unicode.IsDigit(rs[i]) || rs[i] == '.'); i++ {
+			}
+			tokens = append(tokens, token{tokNumber, string(rs[start:i]), start + 1})
+		case unicode.IsLetter(r) || r == '_':
+			for i++; i < len(rs) && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || rs[i] == '_'); i++ {
+			}
+			tokens = append(tokens, token{tokIdent, string(rs[start:i]), start + 1})
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(string(rs[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, errors.Errorf("unexpected %q at column %d", r, start+1)
+			}
+			tokens = append(tokens, token{tokOp, op, start + 1})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(rs) + 1}), nil
+}
+
+// parser is a recursive descent parser over the grammar
+//
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" or ")" | comparison
+//	comparison = field operator literal
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return errors.Errorf("column %d: %s", p.peek().pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) or() (func(Contributor) bool, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOp && p.peek().text == "||" {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(c Contributor) bool { return l(c) || right(c) }
+	}
+	return left, nil
+}
+
+func (p *parser) and() (func(Contributor) bool, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOp && p.peek().text == "&&" {
+		p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(c Contributor) bool { return l(c) && right(c) }
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (func(Contributor) bool, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && t.text == "!":
+		p.next()
+		f, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(c Contributor) bool { return !f(c) }, nil
+	case t.kind == tokLParen:
+		p.next()
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, p.errorf("expected ) to close ( at column %d but found %s", t.pos, p.peek())
+		}
+		p.next()
+		return f, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (func(Contributor) bool, error) {
+	if p.peek().kind != tokIdent {
+		return nil, p.errorf("expected a field but found %s", p.peek())
+	}
+	field := p.next().text
+	if value := contributorField(field); value != nil {
+		return p.numericComparison(field, value)
+	}
+	for _, f := range textFields {
+		if f == field {
+			return p.textComparison(field)
+		}
+	}
+	p.i--
+	return nil, p.errorf("unknown field %q. Should be one of: %s", field, strings.Join(whereFieldNames(), ", "))
+}
+
+func (p *parser) numericComparison(field string, value func(Contributor) float64) (func(Contributor) bool, error) {
+	op := p.peek()
+	var cmp func(a, b float64) bool
+	switch op.text {
+	case "==":
+		cmp = func(a, b float64) bool { return a == b }
+	case "!=":
+		cmp = func(a, b float64) bool { return a != b }
+	case "<":
+		cmp = func(a, b float64) bool { return a < b }
+	case "<=":
+		cmp = func(a, b float64) bool { return a <= b }
+	case ">":
+		cmp = func(a, b float64) bool { return a > b }
+	case ">=":
+		cmp = func(a, b float64) bool { return a >= b }
+	}
+	if op.kind != tokOp || cmp == nil {
+		return nil, p.errorf("expected one of ==, !=, <, <=, >, >= after %s but found %s", field, op)
+	}
+	p.next()
+
+	if p.peek().kind != tokNumber {
+		return nil, p.errorf("expected a number after %s %s but found %s", field, op.text, p.peek())
+	}
+	n, err := strconv.ParseFloat(p.peek().text, 64)
+	if err != nil {
+		return nil, p.errorf("invalid number %s", p.peek().text)
+	}
+	p.next()
+	return func(c Contributor) bool { return cmp(value(c), n) }, nil
+}
+
+func (p *parser) textComparison(field string) (func(Contributor) bool, error) {
+	op := p.peek()
+	if op.kind != tokOp || (op.text != "==" && op.text != "!=" && op.text != "=~" && op.text != "!~") {
+		return nil, p.errorf("expected one of ==, !=, =~, !~ after %s but found %s", field, op)
+	}
+	p.next()
+
+	if p.peek().kind != tokString {
+		return nil, p.errorf("expected a double quoted string after %s %s but found %s", field, op.text, p.peek())
+	}
+	s := p.peek().text
+	switch op.text {
+	case "==":
+		p.next()
+		return func(c Contributor) bool { return c.Name == s }, nil
+	case "!=":
+		p.next()
+		return func(c Contributor) bool { return c.Name != s }, nil
+	}
+
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return nil, p.errorf("invalid regular expression %s: %v", p.peek(), err)
+	}
+	p.next()
+	if op.text == "!~" {
+		return func(c Contributor) bool { return !re.MatchString(c.Name) }, nil
+	}
+	return func(c Contributor) bool { return re.MatchString(c.Name) }, nil
+}
+
+// whereFieldNames returns the names of the fields that can be used in an expression
+func whereFieldNames() []string {
+	return append(contributorFieldNames(), textFields...)
+}
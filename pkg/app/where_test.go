@@ -0,0 +1,140 @@
+package app_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+)
+
+func TestCompileWhere(t *testing.T) {
+	input := app.WithRepoTotal([]app.Contributor{
+		{Name: "Ron-Swanson", Stats: app.Stats{Additions: 10, Deletions: 30, Commits: 2}},
+		{Name: "Luke-Davies", Stats: app.Stats{Additions: 300, Deletions: 5, Commits: 7}},
+		{Name: "Leslie-Knope", Stats: app.Stats{Additions: 150, Deletions: 0, Commits: 5}},
+		{Name: "dependabot[bot]", Stats: app.Stats{Additions: 900, Deletions: 900, Commits: 40}},
+	})
+
+	ts := []struct {
+		Name      string
+		Input     string
+		Expect    []string
+		ExpectErr error
+	}{
+		{
+			Name:   "And",
+			Input:  `commits >= 5 && additions > 100 && login !~ "bot]$"`,
+			Expect: []string{"Luke-Davies", "Leslie-Knope"},
+		},
+		{
+			// && binds tighter than ||
+			Name:   "Precedence",
+			Input:  `name == "Ron-Swanson" || commits > 5 && deletions < 10`,
+			Expect: []string{"Ron-Swanson", "Luke-Davies"},
+		},
+		{
+			Name:   "Not And Parentheses",
+			Input:  `!(login =~ "^L" || churn>1000)`,
+			Expect: []string{"Ron-Swanson"},
+		},
+		{
+			Name:   "Negative Number",
+			Input:  `net < -10`,
+			Expect: []string{"Ron-Swanson"},
+		},
+		{
+			// 54 commits and 2295 lines changed in total
+			Name:   "Derived Fields",
+			Input:  `lines_per_commit > 25 && commit_share < 50.5 || line_share >= 78.4`,
+			Expect: []string{"Luke-Davies", "Leslie-Knope", "dependabot[bot]"},
+		},
+		{
+			Name:      "Invalid Number",
+			Input:     `commit_share > 1.2.3`,
+			ExpectErr: fmt.Errorf(`[CompileWhere] invalid expression: column 16: invalid number 1.2.3`),
+		},
+		{
+			Name:   "Escaped String",
+			Input:  `login =~ "\\[bot\\]"`,
+			Expect: []string{"dependabot[bot]"},
+		},
+		{
+			Name:      "Unknown Field",
+			Input:     `commits > 1 && stars > 5`,
+			ExpectErr: fmt.Errorf(`[CompileWhere] invalid expression: column 16: unknown field "stars". Should be one of: commits, additions, deletions, net, churn, lines_per_commit, commit_share, line_share, login, name`),
+		},
+		{
+			Name:      "Missing Value",
+			Input:     `commits >=`,
+			ExpectErr: fmt.Errorf(`[CompileWhere] invalid expression: column 11: expected a number after commits >= but found end of expression`),
+		},
+		{
+			Name:      "String For Number",
+			Input:     `commits > "5"`,
+			ExpectErr: fmt.Errorf(`[CompileWhere] invalid expression: column 11: expected a number after commits > but found "5"`),
+		},
+		{
+			Name:      "Regexp On Number",
+			Input:     `commits =~ "5"`,
+			ExpectErr: fmt.Errorf(`[CompileWhere] invalid expression: column 9: expected one of ==, !=, <, <=, >, >= after commits but found "=~"`),
+		},
+		{
+			Name:      "Ordering On Text",
+			Input:     `login < "b"`,
+			ExpectErr: fmt.Errorf(`[CompileWhere] invalid expression: column 7: expected one of ==, !=, =~, !~ after login but found "<"`),
+		},
+		{
+			Name:      "Invalid Regexp",
+			Input:     `login =~ "("`,
+			ExpectErr: fmt.Errorf("[CompileWhere] invalid expression: column 10: invalid regular expression \"(\": error parsing regexp: missing closing ): `(`"),
+		},
+		{
+			Name:      "Unclosed Parenthesis",
+			Input:     `(commits > 1`,
+			ExpectErr: fmt.Errorf(`[CompileWhere] invalid expression: column 13: expected ) to close ( at column 1 but found end of expression`),
+		},
+		{
+			Name:      "Trailing Input",
+			Input:     `commits > 1 additions > 1`,
+			ExpectErr: fmt.Errorf(`[CompileWhere] invalid expression: column 13: expected && or || but found "additions"`),
+		},
+		{
+			Name:      "Single Equals",
+			Input:     `commits = 1`,
+			ExpectErr: fmt.Errorf(`[CompileWhere] invalid expression: unexpected '=' at column 9`),
+		},
+		{
+			Name:      "Unterminated String",
+			Input:     `login == "bot`,
+			ExpectErr: fmt.Errorf(`[CompileWhere] invalid expression: unterminated string at column 10`),
+		},
+		{
+			Name:      "Empty",
+			Input:     ``,
+			ExpectErr: fmt.Errorf(`[CompileWhere] invalid expression: column 1: expected a field but found end of expression`),
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			f, err := app.CompileWhere(tc.Input)
+			if tc.ExpectErr != nil {
+				if err == nil || err.Error() != tc.ExpectErr.Error() {
+					t.Fatalf("CompileWhere: Have `err`: %v want: %v", err, tc.ExpectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompileWhere: Unexpected Error: %v", err)
+			}
+			var names []string
+			for _, c := range app.FilterContributors(input, f) {
+				names = append(names, c.Name)
+			}
+			if !reflect.DeepEqual(names, tc.Expect) {
+				t.Errorf("CompileWhere: have %v want %v", names, tc.Expect)
+			}
+		})
+	}
+}
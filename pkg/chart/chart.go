@@ -0,0 +1,294 @@
+// Package chart draws simple SVG charts using only the standard library,
+// so that reports can embed them without any external tools.
+package chart
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Series is a named set of values, one per label of the chart.
+// Colour is optional, the Palette is used if empty.
+type Series struct {
+	Name   string
+	Values []float64
+	Colour string
+}
+
+// Options contains the options common to all charts
+// - Title: optional. Drawn above the chart.
+// - Width, Height: size of the SVG in pixels. Zero uses a default. Bars ignores Height as it depends on the labels.
+type Options struct {
+	Title  string
+	Width  int
+	Height int
+}
+
+const (
+	defaultWidth  = 800
+	defaultHeight = 300
+
+	marginTop    = 30
+	marginBottom = 40
+	marginLeft   = 50
+	legendWidth  = 160
+	fontSize     = 11
+)
+
+// Palette is the colour of each series, in order. It repeats if there are more series than colours.
+var Palette = []string{
+	"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f",
+	"#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac",
+}
+
+// colour returns the colour of s, the ith series
+func colour(i int, s Series) string {
+	if s.Colour != "" {
+		return s.Colour
+	}
+	return Palette[i%len(Palette)]
+}
+
+func (o Options) size() (int, int) {
+	w, h := o.Width, o.Height
+	if w <= 0 {
+		w = defaultWidth
+	}
+	if h <= 0 {
+		h = defaultHeight
+	}
+	return w, h
+}
+
+// plot is the area of a vertical chart inside the margins, axes and legend
+type plot struct {
+	x, y, w, h float64
+	max        float64
+}
+
+// scale returns the y coordinate of v
+func (p plot) scale(v float64) float64 {
+	return p.y + p.h - v/p.max*p.h
+}
+
+// StackedBars draws a vertical bar per label, stacking the value of each series
+func StackedBars(w io.Writer, opts Options, labels []string, series []Series) error {
+	max := 0.0
+	for i := range labels {
+		total := 0.0
+		for _, s := range series {
+			total += value(s, i)
+		}
+		max = math.Max(max, total)
+	}
+
+	sw := &svgWriter{w: w}
+	p := sw.frame(opts, labels, series, max)
+
+	bw := p.w / float64(len(labels))
+	for i := range labels {
+		base := 0.0
+		for j, s := range series {
+			v := value(s, i)
+			if v <= 0 {
+				continue
+			}
+			y := p.scale(base + v)
+			sw.printf(`<rect x="%s" y="%s" width="%s" height="%s" fill="%s"><title>%s %s: %s</title></rect>`+"\n",
+				num(p.x+float64(i)*bw+bw*0.1), num(y), num(bw*0.8), num(p.scale(base)-y), colour(j, s),
+				esc(s.Name), esc(labels[i]), num(v))
+			base += v
+		}
+	}
+
+	return sw.end()
+}
+
+// Lines draws a line per series across the labels
+func Lines(w io.Writer, opts Options, labels []string, series []Series) error {
+	max := 0.0
+	for _, s := range series {
+		for _, v := range s.Values {
+			max = math.Max(max, v)
+		}
+	}
+
+	sw := &svgWriter{w: w}
+	p := sw.frame(opts, labels, series, max)
+
+	// points are in the middle of each label's slot, to line up with the labels
+	step := p.w / float64(len(labels))
+	for j, s := range series {
+		sw.printf(`<polyline fill="none" stroke="%s" stroke-width="2" points="`, colour(j, s))
+		for i := range labels {
+			if i > 0 {
+				sw.printf(" ")
+			}
+			sw.printf("%s,%s", num(p.x+step*(float64(i)+0.5)), num(p.scale(value(s, i))))
+		}
+		sw.printf(`"><title>%s</title></polyline>`+"\n", esc(s.Name))
+	}
+
+	return sw.end()
+}
+
+// Bars draws a row per label with a horizontal bar for each series.
+// The height of the chart depends on the number of labels and series.
+func Bars(w io.Writer, opts Options, labels []string, series []Series) error {
+	const (
+		barHeight   = 12
+		rowGap      = 8
+		labelsWidth = 150
+		valuesWidth = 60
+	)
+
+	width, _ := opts.size()
+	rowHeight := float64(barHeight*len(series) + rowGap)
+	top := float64(marginTop)
+	if len(series) > 1 {
+		top += 20 // legend
+	}
+	height := top + rowHeight*float64(len(labels)) + rowGap
+
+	max := 0.0
+	for _, s := range series {
+		for _, v := range s.Values {
+			max = math.Max(max, v)
+		}
+	}
+	if max <= 0 {
+		max = 1
+	}
+	plotWidth := float64(width - labelsWidth - valuesWidth)
+
+	sw := &svgWriter{w: w}
+	sw.start(width, int(math.Ceil(height)), opts.Title)
+
+	if len(series) > 1 {
+		for j, s := range series {
+			x := float64(labelsWidth + j*120)
+			sw.printf(`<rect x="%s" y="%d" width="10" height="10" fill="%s"/>`+"\n", num(x), marginTop-2, colour(j, s))
+			sw.printf(`<text x="%s" y="%d">%s</text>`+"\n", num(x+14), marginTop+7, esc(s.Name))
+		}
+	}
+
+	for i, label := range labels {
+		y := top + rowHeight*float64(i)
+		sw.printf(`<text x="%d" y="%s" text-anchor="end">%s</text>`+"\n", labelsWidth-6, num(y+rowHeight/2), esc(label))
+		for j, s := range series {
+			v := value(s, i)
+			by := y + float64(barHeight*j)
+			bw := v / max * plotWidth
+			sw.printf(`<rect x="%d" y="%s" width="%s" height="%d" fill="%s"><title>%s %s: %s</title></rect>`+"\n",
+				labelsWidth, num(by), num(bw), barHeight-1, colour(j, s), esc(label), esc(s.Name), num(v))
+			sw.printf(`<text x="%s" y="%s">%s</text>`+"\n", num(float64(labelsWidth)+bw+4), num(by+barHeight-2), num(v))
+		}
+	}
+
+	return sw.end()
+}
+
+// value returns the ith value of s, or zero if it doesn't have one
+func value(s Series, i int) float64 {
+	if i < len(s.Values) {
+		return s.Values[i]
+	}
+	return 0
+}
+
+// svgWriter writes SVG elements, keeping the first error
+type svgWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (sw *svgWriter) printf(format string, a ...interface{}) {
+	if sw.err == nil {
+		_, sw.err = fmt.Fprintf(sw.w, format, a...)
+	}
+}
+
+func (sw *svgWriter) start(width, height int, title string) {
+	sw.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="%d">`+"\n",
+		width, height, width, height, fontSize)
+	sw.printf(`<rect width="100%%" height="100%%" fill="#fff"/>` + "\n")
+	if title != "" {
+		sw.printf(`<text x="%d" y="%d" font-size="%d" font-weight="bold">%s</text>`+"\n", marginLeft, marginTop-12, fontSize+3, esc(title))
+	}
+}
+
+func (sw *svgWriter) end() error {
+	sw.printf("</svg>\n")
+	return sw.err
+}
+
+// frame starts a vertical chart, drawing the y axis, the labels and the legend, and returns the plot area.
+// Labels are thinned out so they don't overlap.
+func (sw *svgWriter) frame(opts Options, labels []string, series []Series, max float64) plot {
+	width, height := opts.size()
+	sw.start(width, height, opts.Title)
+
+	step, max := ticks(max)
+	p := plot{
+		x:   marginLeft,
+		y:   marginTop,
+		w:   float64(width - marginLeft - legendWidth),
+		h:   float64(height - marginTop - marginBottom),
+		max: max,
+	}
+
+	for v := 0.0; v <= max; v += step {
+		y := p.scale(v)
+		sw.printf(`<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="#ddd"/>`+"\n", num(p.x), num(y), num(p.x+p.w), num(y))
+		sw.printf(`<text x="%s" y="%s" text-anchor="end">%s</text>`+"\n", num(p.x-4), num(y+4), num(v))
+	}
+
+	if len(labels) > 0 {
+		slot := p.w / float64(len(labels))
+		every := int(math.Ceil(70 / slot)) // roughly the width of a date
+		for i := 0; i < len(labels); i += every {
+			sw.printf(`<text x="%s" y="%s" text-anchor="middle">%s</text>`+"\n",
+				num(p.x+slot*(float64(i)+0.5)), num(p.y+p.h+16), esc(labels[i]))
+		}
+	}
+
+	for j, s := range series {
+		y := marginTop + j*16
+		x := width - legendWidth + 10
+		sw.printf(`<rect x="%d" y="%d" width="10" height="10" fill="%s"/>`+"\n", x, y, colour(j, s))
+		sw.printf(`<text x="%d" y="%d">%s</text>`+"\n", x+14, y+9, esc(s.Name))
+	}
+
+	return p
+}
+
+// ticks returns a round step for the y axis and the max rounded up to it. There are about 5 steps.
+func ticks(max float64) (float64, float64) {
+	if max <= 0 {
+		return 1, 1
+	}
+	raw := max / 5
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := mag
+	for _, m := range []float64{1, 2, 5, 10} {
+		step = m * mag
+		if step >= raw {
+			break
+		}
+	}
+	// counts can't be fractions
+	step = math.Max(1, step)
+	return step, math.Ceil(max/step) * step
+}
+
+// num formats v without trailing zeros
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func esc(s string) string {
+	return html.EscapeString(s)
+}
@@ -0,0 +1,97 @@
+package chart_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/chart"
+)
+
+// elements parses the SVG and returns the number of each element, failing the test if it isn't valid XML
+func elements(t *testing.T, svg string) map[string]int {
+	res := make(map[string]int)
+	dec := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return res
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, svg)
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			res[se.Name.Local]++
+		}
+	}
+}
+
+var (
+	testLabels = []string{"2018-06-03", "2018-06-10", "2018-06-17"}
+	testSeries = []chart.Series{
+		{Name: "Luke-Davies", Values: []float64{1, 0, 4}},
+		{Name: "Ron <Swanson>", Values: []float64{2, 3}, Colour: "#000"},
+	}
+)
+
+func TestStackedBars(t *testing.T) {
+	var buf bytes.Buffer
+	if err := chart.StackedBars(&buf, chart.Options{Title: "Commits"}, testLabels, testSeries); err != nil {
+		t.Fatalf("StackedBars: Unexpected Error: %v", err)
+	}
+
+	els := elements(t, buf.String())
+	// background + 2 legend keys + a bar per non-zero value
+	if els["rect"] != 1+2+4 {
+		t.Errorf("StackedBars: have %d rects want 7:\n%s", els["rect"], buf.String())
+	}
+	// the tallest stack is 4 so the axis goes up to 4 in steps of 1
+	if !strings.Contains(buf.String(), `text-anchor="end">4</text>`) || strings.Contains(buf.String(), `text-anchor="end">5</text>`) {
+		t.Errorf("StackedBars: expected the y axis to go up to 4:\n%s", buf.String())
+	}
+	for _, want := range []string{"Commits", "Ron &lt;Swanson&gt;", `fill="#000"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("StackedBars: output doesn't contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	var buf bytes.Buffer
+	if err := chart.Lines(&buf, chart.Options{Width: 400, Height: 200}, testLabels, testSeries); err != nil {
+		t.Fatalf("Lines: Unexpected Error: %v", err)
+	}
+
+	els := elements(t, buf.String())
+	if els["polyline"] != 2 {
+		t.Errorf("Lines: have %d lines want 2:\n%s", els["polyline"], buf.String())
+	}
+	if !strings.Contains(buf.String(), `width="400" height="200"`) {
+		t.Errorf("Lines: expected a 400x200 chart:\n%s", buf.String())
+	}
+}
+
+func TestBars(t *testing.T) {
+	var buf bytes.Buffer
+	labels := []string{"Luke-Davies", "Ron-Swanson"}
+	series := []chart.Series{{Name: "Additions", Values: []float64{10, 5}}, {Name: "Deletions", Values: []float64{3, 0}}}
+	if err := chart.Bars(&buf, chart.Options{}, labels, series); err != nil {
+		t.Fatalf("Bars: Unexpected Error: %v", err)
+	}
+
+	els := elements(t, buf.String())
+	// background + 2 legend keys + a bar per value
+	if els["rect"] != 1+2+4 {
+		t.Errorf("Bars: have %d rects want 7:\n%s", els["rect"], buf.String())
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := chart.StackedBars(&buf, chart.Options{}, nil, nil); err != nil {
+		t.Fatalf("StackedBars: Unexpected Error: %v", err)
+	}
+	elements(t, buf.String())
+}
@@ -0,0 +1,209 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TokenSource supplies the token used to authenticate requests to GitHub
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token e.g. a personal access token
+type StaticToken string
+
+// Token returns the token
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// AuthTransport is an http.RoundTripper that adds an Authorization header to requests to Host
+// using the token given by Source. Use it as the Transport of the Client's HTTPClient.
+// Host is the host (and port, if any) of the API e.g. api.github.com. Requests to any other host,
+// such as redirects to a download server, are sent without the token. Empty means every host.
+// Base is the underlying RoundTripper; http.DefaultTransport is used if nil.
+type AuthTransport struct {
+	Source TokenSource
+	Host   string
+	Base   http.RoundTripper
+}
+
+// RoundTrip authorises and sends the request
+func (t *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Host != "" && req.URL.Host != t.Host {
+		return transport(t.Base).RoundTrip(req)
+	}
+
+	token, err := t.Source.Token(req.Context())
+	if err != nil {
+		return nil, errors.Wrap(err, "[AuthTransport] error getting token")
+	}
+
+	// RoundTrippers shouldn't modify the request so work on a copy
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = append([]string(nil), v...)
+	}
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	return transport(t.Base).RoundTrip(r)
+}
+
+func transport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		return http.DefaultTransport
+	}
+	return rt
+}
+
+// AppTokenSource is a TokenSource that mints (and caches) installation tokens for a GitHub App.
+// A JWT signed with the App's private key is exchanged for an installation token which is reused
+// until shortly before it expires.
+// Use NewAppTokenSource to create one.
+type AppTokenSource struct {
+	BaseURL        string
+	AppID          int64
+	InstallationID int64
+	PrivateKey     *rsa.PrivateKey
+	// Base is the RoundTripper used to mint tokens; http.DefaultTransport is used if nil.
+	Base http.RoundTripper
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewAppTokenSource creates an AppTokenSource from a PEM encoded private key as downloaded from
+// the GitHub App settings page.
+func NewAppTokenSource(baseURL string, appID, installationID int64, privateKeyPEM []byte) (*AppTokenSource, error) {
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, errors.Wrap(err, "[NewAppTokenSource] invalid private key")
+	}
+	return &AppTokenSource{
+		BaseURL:        baseURL,
+		AppID:          appID,
+		InstallationID: installationID,
+		PrivateKey:     key,
+	}, nil
+}
+
+func parsePrivateKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// Token returns a cached installation token, minting a new one if required
+func (s *AppTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// leave a margin so the token doesn't expire mid-request
+	if s.token != "" && time.Now().Add(time.Minute).Before(s.expires) {
+		return s.token, nil
+	}
+
+	jwt, err := s.jwt(time.Now())
+	if err != nil {
+		return "", errors.Wrap(err, "[AppTokenSource] error creating JWT")
+	}
+
+	u := fmt.Sprintf("%s/app/installations/%d/access_tokens", s.BaseURL, s.InstallationID)
+	req, err := http.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "[AppTokenSource] error creating request for url: %s", u)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("User-Agent", userAgent)
+	req.Header.Add("Accept", acceptHeader)
+	req.Header.Add("Authorization", "Bearer "+jwt)
+
+	resp, err := transport(s.Base).RoundTrip(req)
+	if err != nil {
+		return "", errors.Wrap(err, "[AppTokenSource] error sending request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", errors.Wrap(newAPIError(resp), "[AppTokenSource] could not create installation token")
+	}
+
+	var res struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", errors.Wrap(err, "[AppTokenSource] Error unmarshalling result from GitHub")
+	}
+
+	s.token, s.expires = res.Token, res.ExpiresAt
+	return s.token, nil
+}
+
+// jwt creates the RS256 signed JSON Web Token used to authenticate as the App itself.
+// reference: https://developer.github.com/apps/building-github-apps/authenticating-with-github-apps/
+func (s *AppTokenSource) jwt(now time.Time) (string, error) {
+	enc := base64.RawURLEncoding
+
+	header := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(struct {
+		IssuedAt  int64  `json:"iat"`
+		ExpiresAt int64  `json:"exp"`
+		Issuer    string `json:"iss"`
+	}{
+		// backdated to allow for clock drift. GitHub rejects tokens that live longer than 10 minutes
+		IssuedAt:  now.Add(-time.Minute).Unix(),
+		ExpiresAt: now.Add(9 * time.Minute).Unix(),
+		Issuer:    strconv.FormatInt(s.AppID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	buf.WriteByte('.')
+	buf.WriteString(enc.EncodeToString(claims))
+
+	sum := sha256.Sum256(buf.Bytes())
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.PrivateKey, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+
+	buf.WriteByte('.')
+	buf.WriteString(enc.EncodeToString(sig))
+	return buf.String(), nil
+}
@@ -0,0 +1,160 @@
+package github_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+)
+
+func TestAuthTransport(t *testing.T) {
+	var haveAuth string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		haveAuth = r.Header.Get("Authorization")
+		fmt.Fprint(w, listContributorStatsTestResp)
+	}))
+	defer mockServer.Close()
+
+	client := github.Client{
+		BaseURL:    mockServer.URL,
+		HTTPClient: &http.Client{Transport: &github.AuthTransport{Source: github.StaticToken("test-token")}},
+	}
+	_, err := client.ListContributorStats(context.Background(), "repo-owner", "repo-name")
+	if err != nil {
+		t.Fatalf("ListContributorStats: Unexpected Error: %v", err)
+	}
+
+	if want := "Bearer test-token"; haveAuth != want {
+		t.Errorf("AuthTransport: have Authorization header %q want %q", haveAuth, want)
+	}
+}
+
+func TestAuthTransportRedirect(t *testing.T) {
+	otherAuth := "unset"
+	otherServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherAuth = r.Header.Get("Authorization")
+		fmt.Fprint(w, listContributorStatsTestResp)
+	}))
+	defer otherServer.Close()
+
+	var apiAuth string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiAuth = r.Header.Get("Authorization")
+		http.Redirect(w, r, otherServer.URL+r.URL.Path, http.StatusFound)
+	}))
+	defer mockServer.Close()
+
+	tr := &github.AuthTransport{Source: github.StaticToken("test-token"), Host: strings.TrimPrefix(mockServer.URL, "http://")}
+	client := github.Client{BaseURL: mockServer.URL, HTTPClient: &http.Client{Transport: tr}}
+	if _, err := client.ListContributorStats(context.Background(), "repo-owner", "repo-name"); err != nil {
+		t.Fatalf("ListContributorStats: Unexpected Error: %v", err)
+	}
+
+	if want := "Bearer test-token"; apiAuth != want {
+		t.Errorf("AuthTransport: have Authorization header %q want %q", apiAuth, want)
+	}
+	if otherAuth != "" {
+		t.Errorf("AuthTransport: have Authorization header %q after redirecting to another host want none", otherAuth)
+	}
+}
+
+func TestAppTokenSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal("TestAppTokenSource: Something went wrong generating a key")
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	mints := 0
+	var haveAuth string
+	mockHandler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app/installations/42/access_tokens" {
+			mints++
+			if r.Method != http.MethodPost {
+				t.Errorf("AppTokenSource: have method %s want POST", r.Method)
+			}
+			verifyJWT(t, &key.PublicKey, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), "7")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token":"ghs_test","expires_at":"%s"}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+			return
+		}
+		haveAuth = r.Header.Get("Authorization")
+		fmt.Fprint(w, listContributorStatsTestResp)
+	}
+	mockServer := httptest.NewServer(http.HandlerFunc(mockHandler))
+	defer mockServer.Close()
+
+	src, err := github.NewAppTokenSource(mockServer.URL, 7, 42, keyPEM)
+	if err != nil {
+		t.Fatalf("NewAppTokenSource: Unexpected Error: %v", err)
+	}
+
+	client := github.Client{BaseURL: mockServer.URL, HTTPClient: &http.Client{Transport: &github.AuthTransport{Source: src}}}
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListContributorStats(context.Background(), "repo-owner", "repo-name"); err != nil {
+			t.Fatalf("ListContributorStats: Unexpected Error: %v", err)
+		}
+	}
+
+	if want := "Bearer ghs_test"; haveAuth != want {
+		t.Errorf("AppTokenSource: have Authorization header %q want %q", haveAuth, want)
+	}
+	if mints != 1 {
+		t.Errorf("AppTokenSource: expected token to be cached, have %d tokens minted", mints)
+	}
+}
+
+func TestNewAppTokenSourceBadKey(t *testing.T) {
+	_, err := github.NewAppTokenSource("", 7, 42, []byte("not a key"))
+	if err == nil {
+		t.Fatal("NewAppTokenSource: Expected error but received nil")
+	}
+}
+
+func verifyJWT(t *testing.T, pub *rsa.PublicKey, jwt, wantIssuer string) {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("AppTokenSource: malformed JWT: %q", jwt)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("AppTokenSource: bad JWT signature encoding: %v", err)
+	}
+	sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], sig); err != nil {
+		t.Errorf("AppTokenSource: JWT signature doesn't verify: %v", err)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("AppTokenSource: bad JWT claims encoding: %v", err)
+	}
+	var claims struct {
+		IssuedAt  int64  `json:"iat"`
+		ExpiresAt int64  `json:"exp"`
+		Issuer    string `json:"iss"`
+	}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		t.Fatalf("AppTokenSource: bad JWT claims: %v", err)
+	}
+	if claims.Issuer != wantIssuer {
+		t.Errorf("AppTokenSource: have JWT issuer %q want %q", claims.Issuer, wantIssuer)
+	}
+	if claims.ExpiresAt-claims.IssuedAt > 600 {
+		t.Errorf("AppTokenSource: JWT lives longer than 10 minutes")
+	}
+}
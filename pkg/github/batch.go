@@ -0,0 +1,101 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RepoError is the error for a single repository of a batch
+type RepoError struct {
+	Repo string
+	Err  error
+}
+
+// BatchError is returned by ForEachRepo when the call failed for one or more repositories.
+// Total is the number of repositories in the batch.
+type BatchError struct {
+	Total  int
+	Errors []RepoError
+}
+
+func (e *BatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[ForEachRepo] %d of %d repositories failed:", len(e.Errors), e.Total)
+	for _, re := range e.Errors {
+		fmt.Fprintf(&b, "\n  %s: %s", re.Repo, re.Err)
+	}
+	return b.String()
+}
+
+// ForEachRepo calls f for each of the repos (owner/name), running up to parallelism calls at once.
+// f is given the index of the repo so results can be kept in order. Values below 1 are treated as 1.
+//
+// A failure for one repo doesn't stop the others. Instead, the errors are collected into a *BatchError.
+// Repos that haven't started when ctx is done fail with the context's error.
+// If c.RateLimit.State shows the rate limit is exhausted, repos wait for it to reset if c.RateLimit.Wait is set,
+// otherwise they fail with a *RateLimitError without sending any requests.
+func (c Client) ForEachRepo(ctx context.Context, repos []string, parallelism int, f func(ctx context.Context, i int) error) error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	errs := make([]error, len(repos))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i := range repos {
+		select {
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := c.paceRateLimit(ctx); err != nil {
+				errs[i] = err
+				return
+			}
+			errs[i] = f(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+
+	batchErr := &BatchError{Total: len(repos)}
+	for i, err := range errs {
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, RepoError{Repo: repos[i], Err: err})
+		}
+	}
+	if len(batchErr.Errors) > 0 {
+		return batchErr
+	}
+	return nil
+}
+
+// paceRateLimit checks the rate limit state before a call of a batch
+func (c Client) paceRateLimit(ctx context.Context) error {
+	if c.RateLimit.State == nil {
+		return nil
+	}
+	rate, ok := c.RateLimit.State.Get()
+	if !ok || !rate.Exhausted(time.Now()) {
+		return nil
+	}
+	if !c.RateLimit.Wait {
+		return errors.Wrap(&RateLimitError{APIError: APIError{Message: "skipped, no requests remaining"}, Rate: rate}, "[ForEachRepo]")
+	}
+	if err := c.waitForRateLimit(ctx, rate.Reset); err != nil {
+		return errors.Wrap(err, "[ForEachRepo] gave up waiting for rate limit to reset")
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package github_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+	"github.com/pkg/errors"
+)
+
+func TestForEachRepo(t *testing.T) {
+	repos := []string{"owner/a", "owner/b", "owner/c", "owner/d", "owner/e"}
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	res := make([]string, len(repos))
+
+	err := github.Client{}.ForEachRepo(context.Background(), repos, 2, func(ctx context.Context, i int) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		if repos[i] == "owner/b" || repos[i] == "owner/d" {
+			return fmt.Errorf("boom %d", i)
+		}
+		res[i] = repos[i]
+		return nil
+	})
+
+	if maxRunning != 2 {
+		t.Errorf("ForEachRepo: have %d calls at once want 2", maxRunning)
+	}
+
+	// failures don't stop the other repos
+	if want := []string{"owner/a", "", "owner/c", "", "owner/e"}; fmt.Sprint(res) != fmt.Sprint(want) {
+		t.Errorf("ForEachRepo: have results %q want %q", res, want)
+	}
+
+	batchErr, ok := err.(*github.BatchError)
+	if !ok {
+		t.Fatalf("ForEachRepo: expected *github.BatchError, have %T: %v", err, err)
+	}
+	expect := "[ForEachRepo] 2 of 5 repositories failed:\n  owner/b: boom 1\n  owner/d: boom 3"
+	if batchErr.Total != 5 || batchErr.Error() != expect {
+		t.Errorf("ForEachRepo: have Total %d and error:\n%s\n\nwant Total 5 and error:\n%s", batchErr.Total, batchErr.Error(), expect)
+	}
+}
+
+func TestForEachRepoSuccess(t *testing.T) {
+	err := github.Client{}.ForEachRepo(context.Background(), []string{"owner/a", "owner/b"}, 0, func(ctx context.Context, i int) error {
+		return nil
+	})
+	if err != nil {
+		t.Errorf("ForEachRepo: Unexpected Error: %v", err)
+	}
+}
+
+func TestForEachRepoCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := github.Client{}.ForEachRepo(ctx, []string{"owner/a", "owner/b", "owner/c"}, 1, func(ctx context.Context, i int) error {
+		calls++
+		return ctx.Err()
+	})
+
+	batchErr, ok := err.(*github.BatchError)
+	if !ok || len(batchErr.Errors) != 3 {
+		t.Fatalf("ForEachRepo: expected every repo to fail, have: %v", err)
+	}
+	// a repo may already have been started when the select picks the cancelled context, but not all of them
+	if calls == 3 {
+		t.Error("ForEachRepo: expected repos not to be started after the context is done")
+	}
+	for _, re := range batchErr.Errors {
+		if errors.Cause(re.Err) != context.Canceled {
+			t.Errorf("ForEachRepo: have error for %s: %v want %v", re.Repo, re.Err, context.Canceled)
+		}
+	}
+}
+
+func TestForEachRepoRateLimitExhausted(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		fmt.Fprint(w, listContributorStatsTestResp)
+	}))
+	defer mockServer.Close()
+
+	client := github.Client{BaseURL: mockServer.URL, RateLimit: github.RateLimitOpts{State: &github.RateLimitState{}}}
+	repos := []string{"owner/a", "owner/b"}
+
+	calls := 0
+	err := client.ForEachRepo(context.Background(), repos, 1, func(ctx context.Context, i int) error {
+		calls++
+		// uses the last request
+		_, err := client.ListContributorStats(ctx, "owner", repos[i])
+		return err
+	})
+
+	if calls != 1 {
+		t.Errorf("ForEachRepo: have %d calls want 1", calls)
+	}
+
+	batchErr, ok := err.(*github.BatchError)
+	if !ok || len(batchErr.Errors) != 1 || batchErr.Errors[0].Repo != "owner/b" {
+		t.Fatalf("ForEachRepo: expected only owner/b to fail, have: %v", err)
+	}
+	rlErr, ok := errors.Cause(batchErr.Errors[0].Err).(*github.RateLimitError)
+	if !ok || !rlErr.Rate.Reset.Equal(reset) {
+		t.Errorf("ForEachRepo: expected *github.RateLimitError resetting at %s, have: %v", reset, batchErr.Errors[0].Err)
+	}
+}
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Commit represents a commit returned by GitHub
+// - BUT only the parts we're interested in.
+// Author is the GitHub user and is nil if the commit's email isn't linked to a GitHub account.
+// Stats is only sent by GetCommit, not ListCommits. Merge commits have more than one of Parents.
+type Commit struct {
+	SHA     string        `json:"sha"`
+	Author  *Author       `json:"author"`
+	Commit  CommitDetails `json:"commit"`
+	Stats   CommitStats   `json:"stats"`
+	Parents []CommitRef   `json:"parents"`
+}
+
+// CommitRef represents a reference to another commit e.g. a parent
+type CommitRef struct {
+	SHA string `json:"sha"`
+}
+
+// CommitDetails represents the git details of a commit returned by GitHub
+// - BUT only the parts we're interested in.
+type CommitDetails struct {
+	Author CommitAuthor `json:"author"`
+}
+
+// CommitAuthor represents the git author of a commit returned by GitHub
+type CommitAuthor struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// CommitStats represents the stats of a commit returned by GitHub
+type CommitStats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Total     int `json:"total"`
+}
+
+// ListCommits will call the GitHub API for the given repo (owner/name) and return the commits
+// (without stats) of the default branch between since and until. Zero values are ignored.
+// Every page of results is fetched.
+func (c Client) ListCommits(ctx context.Context, repoOwner, repoName string, since, until time.Time) (*[]Commit, error) {
+	q := url.Values{"per_page": {"100"}}
+	if !since.IsZero() {
+		q.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		q.Set("until", until.UTC().Format(time.RFC3339))
+	}
+	u := fmt.Sprintf("%s/repos/%s/%s/commits?%s", c.BaseURL, repoOwner, repoName, q.Encode())
+
+	res := []Commit{}
+	for u != "" {
+		var page []Commit
+		var next string
+		err := c.poll(ctx, "ListCommits", func(ctx context.Context) error {
+			h, err := c.get(ctx, "ListCommits", u, &page)
+			next = nextPage(h)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, page...)
+		u = next
+	}
+
+	return &res, nil
+}
+
+// GetCommit will call the GitHub API for the given repo (owner/name) and return the commit,
+// including its stats.
+func (c Client) GetCommit(ctx context.Context, repoOwner, repoName, sha string) (*Commit, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s/commits/%s", c.BaseURL, repoOwner, repoName, sha)
+
+	var res Commit
+	err := c.poll(ctx, "GetCommit", func(ctx context.Context) error {
+		_, err := c.get(ctx, "GetCommit", u, &res)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,85 @@
+package github_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+)
+
+func TestListCommits(t *testing.T) {
+	since := time.Date(2018, 6, 18, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2018, 6, 23, 0, 0, 0, 0, time.UTC)
+
+	var mockServer *httptest.Server
+	mockHandler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/repo-owner/repo-name/commits" {
+			t.Errorf("ListCommits: unexpected request path %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("since") != "2018-06-18T00:00:00Z" || q.Get("until") != "2018-06-23T00:00:00Z" {
+			t.Errorf("ListCommits: unexpected query %s", r.URL.RawQuery)
+		}
+
+		if q.Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%[1]s/repos/repo-owner/repo-name/commits?page=2&since=2018-06-18T00:00:00Z&until=2018-06-23T00:00:00Z>; rel="next", <%[1]s/x?page=2>; rel="last"`, mockServer.URL))
+			fmt.Fprint(w, `[{"sha":"abc","author":{"login":"Luke-Davies"},"commit":{"author":{"name":"Luke","email":"luke@example.com","date":"2018-06-20T10:00:00Z"}}}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"sha":"def","author":null,"commit":{"author":{"name":"Ron","email":"ron@example.com","date":"2018-06-19T10:00:00Z"}},"parents":[{"sha":"abc"},{"sha":"123"}]}]`)
+	}
+	mockServer = httptest.NewServer(http.HandlerFunc(mockHandler))
+	defer mockServer.Close()
+
+	client := github.Client{BaseURL: mockServer.URL}
+	res, err := client.ListCommits(context.Background(), "repo-owner", "repo-name", since, until)
+	if err != nil {
+		t.Fatalf("ListCommits: Unexpected Error: %v", err)
+	}
+
+	want := &[]github.Commit{
+		{
+			SHA:    "abc",
+			Author: &github.Author{Login: "Luke-Davies"},
+			Commit: github.CommitDetails{Author: github.CommitAuthor{Name: "Luke", Email: "luke@example.com", Date: time.Date(2018, 6, 20, 10, 0, 0, 0, time.UTC)}},
+		},
+		{
+			SHA:     "def",
+			Commit:  github.CommitDetails{Author: github.CommitAuthor{Name: "Ron", Email: "ron@example.com", Date: time.Date(2018, 6, 19, 10, 0, 0, 0, time.UTC)}},
+			Parents: []github.CommitRef{{SHA: "abc"}, {SHA: "123"}},
+		},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("ListCommits:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
+
+func TestGetCommit(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI != "/repos/repo-owner/repo-name/commits/abc" {
+			t.Errorf("GetCommit: unexpected request url %s", r.RequestURI)
+		}
+		fmt.Fprint(w, `{"sha":"abc","author":{"login":"Luke-Davies"},"stats":{"additions":10,"deletions":2,"total":12}}`)
+	}))
+	defer mockServer.Close()
+
+	client := github.Client{BaseURL: mockServer.URL}
+	res, err := client.GetCommit(context.Background(), "repo-owner", "repo-name", "abc")
+	if err != nil {
+		t.Fatalf("GetCommit: Unexpected Error: %v", err)
+	}
+
+	want := &github.Commit{
+		SHA:    "abc",
+		Author: &github.Author{Login: "Luke-Davies"},
+		Stats:  github.CommitStats{Additions: 10, Deletions: 2, Total: 12},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("GetCommit:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
@@ -0,0 +1,44 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// ErrStatsPending is the cause of the error returned when GitHub is still computing the requested
+// stats (202) and polling is disabled or gave up.
+// Use errors.Cause to check for it.
+var ErrStatsPending = errors.New("[GitHub Error] GitHub sent a 202, meaning they don't have those stats ready. Try again in a minute")
+
+// APIError is the cause of the error returned when GitHub responds with an unsuccessful status code.
+// Message and DocumentationURL are taken from the response body, if GitHub sent them.
+// Use errors.Cause to check for it.
+type APIError struct {
+	StatusCode       int    `json:"-"`
+	Message          string `json:"message"`
+	DocumentationURL string `json:"documentation_url"`
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("[GitHub Error] Did not get successful response from github. Received %d", e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.DocumentationURL != "" {
+		msg += " (" + e.DocumentationURL + ")"
+	}
+	return msg
+}
+
+// newAPIError creates an APIError from the response. The body is consumed.
+func newAPIError(resp *http.Response) *APIError {
+	e := &APIError{}
+	// the body is only a nice to have so decoding errors are ignored
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(e)
+	e.StatusCode = resp.StatusCode
+	return e
+}
@@ -1,5 +1,5 @@
 // Package github provides a client to the GitHub API v3.
-// Only the method the app needs is implemented (ListContributorStats)
+// Only the methods the app needs are implemented (the repository statistics endpoints)
 // and only the fields the app is interested in a specified on the structs.
 package github
 
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -19,7 +21,31 @@ const (
 
 // Client represents a client to the Github API v3
 type Client struct {
+	// BaseURL is the root of the API e.g. https://api.github.com or https://[hostname]/api/v3 for GitHub Enterprise.
 	BaseURL string
+	// HTTPClient is used to send requests, a zero http.Client if nil.
+	// Use an AuthTransport for authenticated requests and NewTransport for custom TLS options.
+	HTTPClient *http.Client
+	// Poll controls how the client waits for GitHub to compute stats.
+	// The zero value disables polling so a 202 is returned as an error straight away.
+	Poll PollOpts
+	// RateLimit controls how the client handles GitHub rate limits.
+	// The zero value returns a *RateLimitError (wrapped) as soon as a limit is hit.
+	RateLimit RateLimitOpts
+}
+
+// PollOpts contains the options used when GitHub responds with a 202 (stats not ready yet)
+// - Interval: how long to wait before the first retry. Zero disables polling.
+// - Backoff: multiplier applied to the wait after each attempt. Values below 1 are treated as 1.
+// - MaxInterval: upper bound for the wait between attempts. Zero means no upper bound.
+// - Timeout: how long to keep polling after the first 202, not counting rate limit waits. Zero means no limit.
+// - OnPending: optional hook called before each wait, useful for progress indicators.
+type PollOpts struct {
+	Interval    time.Duration
+	Backoff     float64
+	MaxInterval time.Duration
+	Timeout     time.Duration
+	OnPending   func(attempt int, wait time.Duration)
 }
 
 // ContributorStats represents the contributor stats returned by GitHub
@@ -33,6 +59,13 @@ type ContributorStats struct {
 // - BUT only the parts we're interested in.
 type Author struct {
 	Login string `json:"login"`
+	Type  string `json:"type"` // User, Organization or Bot
+}
+
+// IsBot reports whether the author is a bot account i.e. GitHub says it's a Bot or,
+// for accounts GitHub doesn't type e.g. git authors, its login ends in [bot] like dependabot[bot].
+func (a Author) IsBot() bool {
+	return a.Type == "Bot" || strings.HasSuffix(a.Login, "[bot]")
 }
 
 // Week represents the weekly stats returned by GitHub
@@ -47,16 +80,133 @@ type Week struct {
 // ListContributorStats will call the GitHub API for the given repo (owner/name) and return the
 // Contributor stats given by GitHub.
 // GitHub groups commits, additions and deletions by "week beginning".
+//
+// If GitHub hasn't computed the stats yet (202) the request is retried according to c.Poll.
 func (c Client) ListContributorStats(ctx context.Context, repoOwner, repoName string) (*[]ContributorStats, error) {
-	h := http.Client{}
 	csURL := fmt.Sprintf("%s/repos/%s/%s/stats/contributors", c.BaseURL, repoOwner, repoName)
 
-	req, err := http.NewRequest(http.MethodGet, csURL, nil)
+	var res []ContributorStats
+	err := c.poll(ctx, "ListContributorStats", func(ctx context.Context) error {
+		return c.getStats(ctx, "ListContributorStats", csURL, &res)
+	})
 	if err != nil {
-		return nil, errors.Wrapf(err, "[ListContributorStats] error creating request for url: %s", csURL)
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// poll calls f until it returns something other than ErrStatsPending, waiting between
+// attempts as configured by c.Poll. op is the name of the calling method, used in errors.
+// If c.RateLimit.Wait is set, attempts are also delayed until any known rate limit resets,
+// and requests rejected by a rate limit are retried up to c.RateLimit.MaxRetries times.
+// c.Poll.Timeout starts with the first 202 and doesn't count the time spent waiting for rate limits.
+func (c Client) poll(ctx context.Context, op string, f func(ctx context.Context) error) error {
+	var deadline time.Time
+	// waitForRateLimit pushes the poll deadline back by however long it waits
+	waitForRateLimit := func(until time.Time) error {
+		start := time.Now()
+		err := c.waitForRateLimit(ctx, until)
+		if !deadline.IsZero() {
+			deadline = deadline.Add(time.Since(start))
+		}
+		return err
 	}
 
-	// overkill for this but good habit
+	wait := c.Poll.Interval
+	rateLimitWait, retries := c.RateLimit.minWait(), 0
+	for attempt := 1; ; {
+		if c.RateLimit.Wait && c.RateLimit.State != nil {
+			if rate, ok := c.RateLimit.State.Get(); ok && rate.Exhausted(time.Now()) {
+				if err := waitForRateLimit(rate.Reset); err != nil {
+					return errors.Wrapf(err, "[%s] gave up waiting for rate limit to reset", op)
+				}
+			}
+		}
+
+		err := f(ctx)
+
+		if rlErr, ok := errors.Cause(err).(*RateLimitError); ok && c.RateLimit.Wait {
+			if retries == c.RateLimit.maxRetries() {
+				return errors.Wrapf(rlErr, "[%s] still rate limited after %d retries", op, retries)
+			}
+			now := time.Now()
+			until := rlErr.Until(now)
+			if min := now.Add(rateLimitWait); until.Before(min) {
+				until = min
+			}
+			if werr := waitForRateLimit(until); werr != nil {
+				return errors.Wrapf(rlErr, "[%s] gave up waiting for rate limit to reset (%v)", op, werr)
+			}
+			rateLimitWait *= 2
+			retries++
+			continue
+		}
+
+		if err != ErrStatsPending {
+			return err
+		}
+		if c.Poll.Interval <= 0 {
+			return errors.Wrapf(err, "[%s]", op)
+		}
+		if c.Poll.Timeout > 0 && deadline.IsZero() {
+			deadline = time.Now().Add(c.Poll.Timeout)
+		}
+
+		if c.Poll.OnPending != nil {
+			c.Poll.OnPending(attempt, wait)
+		}
+
+		if serr := sleepUntil(ctx, wait, deadline); serr != nil {
+			return errors.Wrapf(err, "[%s] gave up waiting for GitHub to compute stats after %d attempts (%v)", op, attempt, serr)
+		}
+
+		wait = nextWait(wait, c.Poll.Backoff, c.Poll.MaxInterval)
+		attempt++
+	}
+}
+
+// sleepUntil is sleep, giving up at deadline unless it's zero
+func sleepUntil(ctx context.Context, d time.Duration, deadline time.Time) error {
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	return sleep(ctx, d)
+}
+
+func nextWait(wait time.Duration, backoff float64, max time.Duration) time.Duration {
+	if backoff > 1 {
+		wait = time.Duration(float64(wait) * backoff)
+	}
+	if max > 0 && wait > max {
+		wait = max
+	}
+	return wait
+}
+
+// getStats performs a single GET against one of the stats endpoints and decodes the body into v.
+// Returns ErrStatsPending (unwrapped) if GitHub responds with a 202. op is the name of the calling method.
+func (c Client) getStats(ctx context.Context, op, u string, v interface{}) error {
+	_, err := c.get(ctx, op, u, v)
+	return err
+}
+
+// get performs a single GET and decodes the body into v, returning the response headers.
+// Returns ErrStatsPending (unwrapped) if GitHub responds with a 202. op is the name of the calling method.
+func (c Client) get(ctx context.Context, op, u string, v interface{}) (http.Header, error) {
+	h := c.HTTPClient
+	if h == nil {
+		h = &http.Client{}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[%s] error creating request for url: %s", op, u)
+	}
+
+	// needed so that polling can be cancelled
 	req = req.WithContext(ctx)
 
 	req.Header.Add("User-Agent", userAgent)
@@ -64,27 +214,51 @@ func (c Client) ListContributorStats(ctx context.Context, repoOwner, repoName st
 
 	resp, err := h.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "[ListContributorStats] error sending request")
+		return nil, errors.Wrapf(err, "[%s] error sending request", op)
+	}
+
+	defer resp.Body.Close()
+
+	if rlErr := c.checkRateLimit(resp); rlErr != nil {
+		return resp.Header, errors.Wrapf(rlErr, "[%s]", op)
 	}
 
 	if resp.StatusCode == http.StatusAccepted {
-		return nil, errors.New("[ListContributorStats] [GitHub Error] GitHub sent a 202, meaning they don't have those stats ready. Try again in a minute")
+		return resp.Header, ErrStatsPending
+	}
+	// GitHub sends a 204 for some stats of empty repos
+	if resp.StatusCode == http.StatusNoContent {
+		return resp.Header, nil
 	}
 	// TODO: what about redirects?
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.Errorf("[ListContributorStats] [GitHub Error] Did not get successful response from github. Received %d", resp.StatusCode)
+		return resp.Header, errors.Wrapf(newAPIError(resp), "[%s]", op)
 	}
 
-	defer resp.Body.Close()
-
 	dec := json.NewDecoder(resp.Body)
-	var res []ContributorStats
-	err = dec.Decode(&res)
+	err = dec.Decode(v)
 	// don't really need to check err here since the next statement would return it anyway
 	// but generally a good habit. (NB: if err nil errors.Wrap returns nil)
 	if err != nil {
-		return nil, errors.Wrap(err, "[ListContributorStats] Error unmarshalling result from GitHub")
+		return resp.Header, errors.Wrapf(err, "[%s] Error unmarshalling result from GitHub", op)
 	}
 
-	return &res, nil
+	return resp.Header, nil
+}
+
+// nextPage returns the URL of the next page given in the Link header, or "" if this is the last page.
+// reference: https://developer.github.com/v3/#pagination
+func nextPage(h http.Header) string {
+	for _, link := range strings.Split(h.Get("Link"), ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+		for _, p := range parts[1:] {
+			if strings.TrimSpace(p) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			}
+		}
+	}
+	return ""
 }
@@ -8,15 +8,18 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+	"github.com/pkg/errors"
 )
 
 var listContributorStatsTestResp = `[
 	{
 		"author": {
 			"login": "Luke-Davies",
-			"id": 99999999
+			"id": 99999999,
+			"type": "User"
 		},
 		"total": 10,
 		"weeks": [
@@ -37,7 +40,8 @@ var listContributorStatsTestResp = `[
 	{
 		"author": {
 			"login": "Ron-Swanson",
-			"id": 88888888
+			"id": 88888888,
+			"type": "User"
 		},
 		"total": 50,
 		"weeks": [
@@ -61,6 +65,7 @@ var testContributorStats = []github.ContributorStats{
 	{
 		Author: github.Author{
 			Login: "Luke-Davies",
+			Type:  "User",
 		},
 		Weeks: []github.Week{
 			{
@@ -80,6 +85,7 @@ var testContributorStats = []github.ContributorStats{
 	{
 		Author: github.Author{
 			Login: "Ron-Swanson",
+			Type:  "User",
 		},
 		Weeks: []github.Week{
 			{
@@ -107,6 +113,7 @@ func TestListContributorStats(t *testing.T) {
 		RepoName    string
 		ExpectRes   *[]github.ContributorStats
 		ExpectError error
+		ExpectCause error
 	}{
 		{
 			Name:       "Happy Path",
@@ -122,15 +129,26 @@ func TestListContributorStats(t *testing.T) {
 			RespHeader:  http.StatusAccepted,
 			RepoOwner:   "repo-owner",
 			RepoName:    "repo-name",
-			ExpectError: fmt.Errorf("[ListContributorStats] [GitHub Error] GitHub sent a 202, meaning they don't have those stats ready. Try again in a minute"),
+			ExpectError: fmt.Errorf("[ListContributorStats]: [GitHub Error] GitHub sent a 202, meaning they don't have those stats ready. Try again in a minute"),
+			ExpectCause: github.ErrStatsPending,
 		},
 		{
 			Name:        "GitHub 404",
-			RespBody:    `{}`,
+			RespBody:    `{"message":"Not Found","documentation_url":"https://developer.github.com/v3"}`,
 			RespHeader:  http.StatusNotFound,
 			RepoOwner:   "repo-owner",
 			RepoName:    "repo-name",
-			ExpectError: fmt.Errorf("[ListContributorStats] [GitHub Error] Did not get successful response from github. Received 404"),
+			ExpectError: fmt.Errorf("[ListContributorStats]: [GitHub Error] Did not get successful response from github. Received 404: Not Found (https://developer.github.com/v3)"),
+			ExpectCause: &github.APIError{StatusCode: http.StatusNotFound, Message: "Not Found", DocumentationURL: "https://developer.github.com/v3"},
+		},
+		{
+			Name:        "GitHub 401 No Body",
+			RespBody:    ``,
+			RespHeader:  http.StatusUnauthorized,
+			RepoOwner:   "repo-owner",
+			RepoName:    "repo-name",
+			ExpectError: fmt.Errorf("[ListContributorStats]: [GitHub Error] Did not get successful response from github. Received 401"),
+			ExpectCause: &github.APIError{StatusCode: http.StatusUnauthorized},
 		},
 		{
 			Name:        "Bad Data",
@@ -170,6 +188,10 @@ func TestListContributorStats(t *testing.T) {
 				}
 			}
 
+			if tc.ExpectCause != nil && !reflect.DeepEqual(errors.Cause(err), tc.ExpectCause) {
+				t.Errorf("ListContributorStats:\n\nhave error cause:\n%#v\n\nwant error cause:\n%#v", errors.Cause(err), tc.ExpectCause)
+			}
+
 			if tc.ExpectRes != nil {
 				if !reflect.DeepEqual(res, tc.ExpectRes) {
 					t.Errorf("ListContributorStats:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, tc.ExpectRes)
@@ -178,3 +200,135 @@ func TestListContributorStats(t *testing.T) {
 		})
 	}
 }
+
+func TestListContributorStatsPolling(t *testing.T) {
+	ts := []struct {
+		Name          string
+		PendingResps  int
+		Poll          github.PollOpts
+		ExpectPending int
+		ExpectError   error
+	}{
+		{
+			Name:          "Ready After Polling",
+			PendingResps:  2,
+			Poll:          github.PollOpts{Interval: time.Millisecond, Backoff: 2},
+			ExpectPending: 2,
+		},
+		{
+			Name:          "Polling Disabled",
+			PendingResps:  1,
+			ExpectPending: 0,
+			ExpectError:   fmt.Errorf("[ListContributorStats]: [GitHub Error] GitHub sent a 202"),
+		},
+		{
+			Name:          "Timeout",
+			PendingResps:  1000,
+			Poll:          github.PollOpts{Interval: 20 * time.Millisecond, Timeout: 50 * time.Millisecond},
+			ExpectPending: -1, // depends on timing
+			ExpectError:   fmt.Errorf("[ListContributorStats] gave up waiting for GitHub to compute stats"),
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			reqs := 0
+			mockHandler := func(w http.ResponseWriter, r *http.Request) {
+				reqs++
+				if reqs <= tc.PendingResps {
+					w.WriteHeader(http.StatusAccepted)
+					fmt.Fprint(w, `{}`)
+					return
+				}
+				fmt.Fprint(w, listContributorStatsTestResp)
+			}
+			mockServer := httptest.NewServer(http.HandlerFunc(mockHandler))
+			defer mockServer.Close()
+
+			pending := 0
+			var waits []time.Duration
+			tc.Poll.OnPending = func(attempt int, wait time.Duration) {
+				pending++
+				waits = append(waits, wait)
+				if attempt != pending {
+					t.Errorf("ListContributorStats: have attempt %d want %d", attempt, pending)
+				}
+			}
+
+			client := github.Client{BaseURL: mockServer.URL, Poll: tc.Poll}
+			res, err := client.ListContributorStats(context.Background(), "repo-owner", "repo-name")
+
+			if tc.ExpectError != nil {
+				if err == nil {
+					t.Fatal("ListContributorStats: Expected error but received nil")
+				}
+				if !strings.HasPrefix(err.Error(), tc.ExpectError.Error()) {
+					t.Errorf("ListContributorStats:\n\nhave error:\n%+v\n\nwant error that starts with:\n%+v", err.Error(), tc.ExpectError.Error())
+				}
+				if errors.Cause(err) != github.ErrStatsPending {
+					t.Errorf("ListContributorStats: have error cause %v want ErrStatsPending", errors.Cause(err))
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("ListContributorStats: Unexpected Error: %v", err)
+				}
+				if !reflect.DeepEqual(res, &testContributorStats) {
+					t.Errorf("ListContributorStats:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, &testContributorStats)
+				}
+			}
+
+			if tc.ExpectPending >= 0 && pending != tc.ExpectPending {
+				t.Errorf("ListContributorStats: have %d pending callbacks want %d", pending, tc.ExpectPending)
+			}
+
+			for i := 1; i < len(waits) && tc.Poll.Backoff > 1; i++ {
+				if waits[i] <= waits[i-1] {
+					t.Errorf("ListContributorStats: expected wait to back off, have %v", waits)
+				}
+			}
+		})
+	}
+}
+
+func TestListContributorStatsPollingCancelled(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := github.Client{
+		BaseURL: mockServer.URL,
+		Poll: github.PollOpts{
+			Interval:  time.Hour,
+			OnPending: func(int, time.Duration) { cancel() },
+		},
+	}
+
+	_, err := client.ListContributorStats(ctx, "repo-owner", "repo-name")
+	if err == nil {
+		t.Fatal("ListContributorStats: Expected error but received nil")
+	}
+	if !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("ListContributorStats: have error %q, want cancellation", err.Error())
+	}
+}
+
+func TestAuthorIsBot(t *testing.T) {
+	ts := []struct {
+		Author github.Author
+		Expect bool
+	}{
+		{Author: github.Author{Login: "Luke-Davies", Type: "User"}, Expect: false},
+		{Author: github.Author{Login: "renovate[bot]", Type: "Bot"}, Expect: true},
+		{Author: github.Author{Login: "some-app", Type: "Bot"}, Expect: true},
+		{Author: github.Author{Login: "dependabot[bot]"}, Expect: true},
+		{Author: github.Author{Login: "robot"}, Expect: false},
+	}
+
+	for _, tc := range ts {
+		if res := tc.Author.IsBot(); res != tc.Expect {
+			t.Errorf("IsBot: Have %t for %+v want: %t", res, tc.Author, tc.Expect)
+		}
+	}
+}
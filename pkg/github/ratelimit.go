@@ -0,0 +1,176 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Defaults for RateLimitOpts
+const (
+	DefaultRateLimitMinWait    = time.Minute
+	DefaultRateLimitMaxRetries = 5
+)
+
+// RateLimitOpts contains the options for handling GitHub rate limits
+// - State: optional. Updated with the rate limit reported in every response. Share it between clients to pace batch runs.
+// - Wait: sleep until the limit resets and retry, instead of returning a *RateLimitError.
+// - MinWait: the shortest wait before a retry, doubled after each one. It covers rejections that don't say when
+// to retry, or say it's already time e.g. a reset in the past because of clock skew. Zero means DefaultRateLimitMinWait.
+// - MaxRetries: how many times a rejected request is retried before the *RateLimitError is returned.
+// Zero means DefaultRateLimitMaxRetries.
+// - OnWait: optional hook called before sleeping, useful for progress indicators.
+type RateLimitOpts struct {
+	State      *RateLimitState
+	Wait       bool
+	MinWait    time.Duration
+	MaxRetries int
+	OnWait     func(until time.Time)
+}
+
+func (o RateLimitOpts) minWait() time.Duration {
+	if o.MinWait > 0 {
+		return o.MinWait
+	}
+	return DefaultRateLimitMinWait
+}
+
+func (o RateLimitOpts) maxRetries() int {
+	if o.MaxRetries > 0 {
+		return o.MaxRetries
+	}
+	return DefaultRateLimitMaxRetries
+}
+
+// RateLimit represents the rate limit reported by GitHub in the X-RateLimit-* headers
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Exhausted returns true if there are no requests remaining before the limit resets
+func (r RateLimit) Exhausted(now time.Time) bool {
+	return r.Remaining <= 0 && r.Reset.After(now)
+}
+
+// RateLimitError is the cause of the error returned when GitHub refuses a request because a rate limit has been hit.
+// RetryAfter is set from the Retry-After header (sent for secondary/abuse limits), in seconds or as an HTTP date,
+// and is zero otherwise.
+// Use errors.Cause to check for it.
+type RateLimitError struct {
+	APIError
+	Rate       RateLimit
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s. Rate limit exceeded, retry after %s", e.APIError.Error(), e.RetryAfter)
+	}
+	return fmt.Sprintf("%s. Rate limit of %d requests exceeded, resets at %s", e.APIError.Error(), e.Rate.Limit, e.Rate.Reset.Format(time.RFC3339))
+}
+
+// Until returns the time at which the request can be retried.
+// It's now if GitHub didn't say, see RateLimitOpts.MinWait.
+func (e *RateLimitError) Until(now time.Time) time.Time {
+	if e.RetryAfter > 0 {
+		return now.Add(e.RetryAfter)
+	}
+	if e.Rate.Reset.After(now) {
+		return e.Rate.Reset
+	}
+	return now
+}
+
+// RateLimitState tracks the latest rate limit reported by GitHub.
+// It is safe for concurrent use.
+type RateLimitState struct {
+	mu    sync.Mutex
+	rate  RateLimit
+	known bool
+}
+
+// Get returns the latest rate limit and whether any has been reported yet
+func (s *RateLimitState) Get() (RateLimit, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rate, s.known
+}
+
+func (s *RateLimitState) update(r RateLimit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rate, s.known = r, true
+}
+
+// parseRateLimit reads the X-RateLimit-* headers. Returns false if they weren't all present.
+func parseRateLimit(h http.Header) (RateLimit, bool) {
+	limit, err1 := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	remaining, err2 := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, err3 := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return RateLimit{}, false
+	}
+	return RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}, true
+}
+
+// checkRateLimit records the rate limit reported in resp and returns a *RateLimitError if
+// the response is a rate limit rejection, in which case the body is consumed.
+// GitHub uses a 403 with no remaining requests for the primary limit and a 403 or 429
+// with a Retry-After header for secondary limits.
+func (c Client) checkRateLimit(resp *http.Response) *RateLimitError {
+	rate, ok := parseRateLimit(resp.Header)
+	if ok && c.RateLimit.State != nil {
+		c.RateLimit.State.update(rate)
+	}
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	hasRetryAfter := resp.Header.Get("Retry-After") != ""
+	if resp.StatusCode == http.StatusTooManyRequests || hasRetryAfter || (ok && rate.Remaining == 0) {
+		return &RateLimitError{APIError: *newAPIError(resp), Rate: rate, RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// parseRetryAfter parses the Retry-After header, either a number of seconds or an HTTP date.
+// Returns zero if it's missing, invalid or in the past.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// waitForRateLimit sleeps until the given time, calling the OnWait hook first.
+func (c Client) waitForRateLimit(ctx context.Context, until time.Time) error {
+	if c.RateLimit.OnWait != nil {
+		c.RateLimit.OnWait(until)
+	}
+	return sleep(ctx, time.Until(until))
+}
+
+// sleep waits for d or until ctx is done, whichever comes first
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,279 @@
+package github_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+	"github.com/pkg/errors"
+)
+
+func TestListContributorStatsRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	ts := []struct {
+		Name             string
+		RespHeader       int
+		Headers          map[string]string
+		ExpectRetryAfter time.Duration
+		ExpectLimited    bool
+	}{
+		{
+			Name:       "Primary Limit",
+			RespHeader: http.StatusForbidden,
+			Headers: map[string]string{
+				"X-RateLimit-Limit":     "60",
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+			},
+			ExpectLimited: true,
+		},
+		{
+			Name:       "Secondary Limit",
+			RespHeader: http.StatusForbidden,
+			Headers: map[string]string{
+				"Retry-After": "30",
+			},
+			ExpectRetryAfter: 30 * time.Second,
+			ExpectLimited:    true,
+		},
+		{
+			Name:          "Too Many Requests",
+			RespHeader:    http.StatusTooManyRequests,
+			ExpectLimited: true,
+		},
+		{
+			Name:       "Forbidden",
+			RespHeader: http.StatusForbidden,
+			Headers: map[string]string{
+				"X-RateLimit-Limit":     "60",
+				"X-RateLimit-Remaining": "59",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+			},
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tc.Headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tc.RespHeader)
+				fmt.Fprint(w, `{"message":"API rate limit exceeded"}`)
+			}))
+			defer mockServer.Close()
+
+			client := github.Client{BaseURL: mockServer.URL}
+			_, err := client.ListContributorStats(context.Background(), "repo-owner", "repo-name")
+			if err == nil {
+				t.Fatal("ListContributorStats: Expected error but received nil")
+			}
+
+			rlErr, limited := errors.Cause(err).(*github.RateLimitError)
+			if limited != tc.ExpectLimited {
+				t.Fatalf("ListContributorStats: have rate limited %t want %t (err: %v)", limited, tc.ExpectLimited, err)
+			}
+			if !limited {
+				return
+			}
+
+			if rlErr.StatusCode != tc.RespHeader || rlErr.Message != "API rate limit exceeded" {
+				t.Errorf("ListContributorStats: have StatusCode %d and Message %q", rlErr.StatusCode, rlErr.Message)
+			}
+			if rlErr.RetryAfter != tc.ExpectRetryAfter {
+				t.Errorf("ListContributorStats: have RetryAfter %s want %s", rlErr.RetryAfter, tc.ExpectRetryAfter)
+			}
+			if tc.ExpectRetryAfter == 0 && tc.Headers != nil && !rlErr.Rate.Reset.Equal(reset) {
+				t.Errorf("ListContributorStats: have Reset %s want %s", rlErr.Rate.Reset, reset)
+			}
+		})
+	}
+}
+
+func TestListContributorStatsWaitForRateLimit(t *testing.T) {
+	reqs := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqs++
+		if reqs == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, listContributorStatsTestResp)
+	}))
+	defer mockServer.Close()
+
+	waits := 0
+	client := github.Client{
+		BaseURL: mockServer.URL,
+		RateLimit: github.RateLimitOpts{
+			Wait:    true,
+			MinWait: time.Millisecond,
+			OnWait:  func(time.Time) { waits++ },
+		},
+	}
+	_, err := client.ListContributorStats(context.Background(), "repo-owner", "repo-name")
+	if err != nil {
+		t.Fatalf("ListContributorStats: Unexpected Error: %v", err)
+	}
+	if reqs != 2 || waits != 1 {
+		t.Errorf("ListContributorStats: have %d requests and %d waits, want 2 and 1", reqs, waits)
+	}
+}
+
+func TestListContributorStatsRateLimitRetries(t *testing.T) {
+	ts := []struct {
+		Name       string
+		Headers    map[string]string
+		ExpectReqs int
+		ExpectErr  string
+	}{
+		{
+			// nothing says when to retry, so only the minimum wait keeps it from retrying straight away
+			Name:       "No Headers",
+			ExpectReqs: 4,
+			ExpectErr:  "still rate limited after 3 retries",
+		},
+		{
+			Name: "Reset In The Past",
+			Headers: map[string]string{
+				"X-RateLimit-Limit":     "60",
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10),
+			},
+			ExpectReqs: 4,
+			ExpectErr:  "still rate limited after 3 retries",
+		},
+		{
+			Name:       "Retry-After Date In The Past",
+			Headers:    map[string]string{"Retry-After": time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)},
+			ExpectReqs: 4,
+			ExpectErr:  "still rate limited after 3 retries",
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			reqs := 0
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reqs++
+				for k, v := range tc.Headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer mockServer.Close()
+
+			var waits []time.Duration
+			client := github.Client{
+				BaseURL: mockServer.URL,
+				RateLimit: github.RateLimitOpts{
+					Wait:       true,
+					MinWait:    5 * time.Millisecond,
+					MaxRetries: 3,
+					OnWait:     func(until time.Time) { waits = append(waits, time.Until(until)) },
+				},
+			}
+			_, err := client.ListContributorStats(context.Background(), "repo-owner", "repo-name")
+			if _, ok := errors.Cause(err).(*github.RateLimitError); !ok || !strings.Contains(err.Error(), tc.ExpectErr) {
+				t.Fatalf("ListContributorStats: Have `err`: %v want a *RateLimitError containing: %s", err, tc.ExpectErr)
+			}
+			if reqs != tc.ExpectReqs {
+				t.Errorf("ListContributorStats: have %d requests want %d", reqs, tc.ExpectReqs)
+			}
+
+			// the minimum wait doubles after each retry
+			for i, w := range waits {
+				if min := (5 * time.Millisecond) << uint(i); w < min-time.Millisecond {
+					t.Errorf("ListContributorStats: have wait %d of %s want at least %s", i+1, w, min)
+				}
+			}
+		})
+	}
+}
+
+func TestListContributorStatsRateLimitOutlastsPollTimeout(t *testing.T) {
+	// rate limited, then not ready, then ready
+	reqs := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqs++
+		switch reqs {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			fmt.Fprint(w, listContributorStatsTestResp)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := github.Client{
+		BaseURL:   mockServer.URL,
+		Poll:      github.PollOpts{Interval: time.Millisecond, Timeout: 20 * time.Millisecond},
+		RateLimit: github.RateLimitOpts{Wait: true, MinWait: 50 * time.Millisecond},
+	}
+	if _, err := client.ListContributorStats(context.Background(), "repo-owner", "repo-name"); err != nil {
+		t.Fatalf("ListContributorStats: Unexpected Error: %v", err)
+	}
+	if reqs != 3 {
+		t.Errorf("ListContributorStats: have %d requests want 3", reqs)
+	}
+}
+
+func TestRateLimitRetryAfterDate(t *testing.T) {
+	retryAt := time.Now().Add(2 * time.Minute).UTC()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", retryAt.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer mockServer.Close()
+
+	client := github.Client{BaseURL: mockServer.URL}
+	_, err := client.ListContributorStats(context.Background(), "repo-owner", "repo-name")
+	rlErr, ok := errors.Cause(err).(*github.RateLimitError)
+	if !ok {
+		t.Fatalf("ListContributorStats: Have `err`: %v want a *RateLimitError", err)
+	}
+	// the date only has whole seconds
+	if rlErr.RetryAfter <= time.Minute || rlErr.RetryAfter > 2*time.Minute {
+		t.Errorf("ListContributorStats: have RetryAfter %s want about 2m", rlErr.RetryAfter)
+	}
+}
+
+func TestRateLimitState(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		fmt.Fprint(w, listContributorStatsTestResp)
+	}))
+	defer mockServer.Close()
+
+	state := &github.RateLimitState{}
+	if _, known := state.Get(); known {
+		t.Fatal("RateLimitState: expected rate limit to be unknown before any requests")
+	}
+
+	client := github.Client{BaseURL: mockServer.URL, RateLimit: github.RateLimitOpts{State: state}}
+	if _, err := client.ListContributorStats(context.Background(), "repo-owner", "repo-name"); err != nil {
+		t.Fatalf("ListContributorStats: Unexpected Error: %v", err)
+	}
+
+	rate, known := state.Get()
+	want := github.RateLimit{Limit: 5000, Remaining: 4999, Reset: reset}
+	if !known || rate.Limit != want.Limit || rate.Remaining != want.Remaining || !rate.Reset.Equal(want.Reset) {
+		t.Errorf("RateLimitState:\n\nhave:\n%+v\n\nwant:\n%+v", rate, want)
+	}
+	if rate.Exhausted(time.Now()) {
+		t.Error("RateLimitState: rate limit should not be exhausted")
+	}
+}
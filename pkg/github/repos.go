@@ -0,0 +1,48 @@
+package github
+
+import (
+	"context"
+	"fmt"
+)
+
+// Repository represents a repository returned by GitHub
+// - BUT only the parts we're interested in.
+type Repository struct {
+	Name     string `json:"name"`
+	Owner    Author `json:"owner"`
+	Fork     bool   `json:"fork"`
+	Archived bool   `json:"archived"`
+}
+
+// ListOrgRepos will call the GitHub API and return the repositories of the given organisation
+// that the client can see. Every page of results is fetched.
+func (c Client) ListOrgRepos(ctx context.Context, org string) (*[]Repository, error) {
+	return c.listRepos(ctx, "ListOrgRepos", fmt.Sprintf("%s/orgs/%s/repos?per_page=100", c.BaseURL, org))
+}
+
+// ListUserRepos will call the GitHub API and return the public repositories of the given user.
+// Every page of results is fetched.
+func (c Client) ListUserRepos(ctx context.Context, user string) (*[]Repository, error) {
+	return c.listRepos(ctx, "ListUserRepos", fmt.Sprintf("%s/users/%s/repos?per_page=100", c.BaseURL, user))
+}
+
+// listRepos fetches every page of repositories starting at u. op is the name of the calling method.
+func (c Client) listRepos(ctx context.Context, op, u string) (*[]Repository, error) {
+	res := []Repository{}
+	for u != "" {
+		var page []Repository
+		var next string
+		err := c.poll(ctx, op, func(ctx context.Context) error {
+			h, err := c.get(ctx, op, u, &page)
+			next = nextPage(h)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, page...)
+		u = next
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,64 @@
+package github_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+)
+
+func TestListOrgRepos(t *testing.T) {
+	var mockServer *httptest.Server
+	mockHandler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/test-org/repos" {
+			t.Errorf("ListOrgRepos: unexpected request path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/test-org/repos?per_page=100&page=2>; rel="next"`, mockServer.URL))
+			fmt.Fprint(w, `[{"name":"repo-a","full_name":"test-org/repo-a","owner":{"login":"test-org"}}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"name":"repo-b","full_name":"test-org/repo-b","owner":{"login":"test-org"},"archived":true}]`)
+	}
+	mockServer = httptest.NewServer(http.HandlerFunc(mockHandler))
+	defer mockServer.Close()
+
+	client := github.Client{BaseURL: mockServer.URL}
+	res, err := client.ListOrgRepos(context.Background(), "test-org")
+	if err != nil {
+		t.Fatalf("ListOrgRepos: Unexpected Error: %v", err)
+	}
+
+	want := &[]github.Repository{
+		{Name: "repo-a", Owner: github.Author{Login: "test-org"}},
+		{Name: "repo-b", Owner: github.Author{Login: "test-org"}, Archived: true},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("ListOrgRepos:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
+
+func TestListUserRepos(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/test-user/repos" {
+			t.Errorf("ListUserRepos: unexpected request path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `[{"name":"repo-a","full_name":"test-user/repo-a","owner":{"login":"test-user"},"fork":true}]`)
+	}))
+	defer mockServer.Close()
+
+	client := github.Client{BaseURL: mockServer.URL}
+	res, err := client.ListUserRepos(context.Background(), "test-user")
+	if err != nil {
+		t.Fatalf("ListUserRepos: Unexpected Error: %v", err)
+	}
+
+	want := &[]github.Repository{{Name: "repo-a", Owner: github.Author{Login: "test-user"}, Fork: true}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("ListUserRepos:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
@@ -0,0 +1,139 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// CommitActivity represents the commits per day of a week returned by GitHub
+// Days starts on Sunday.
+type CommitActivity struct {
+	WeekBeginning int64 `json:"week"`
+	Total         int   `json:"total"`
+	Days          []int `json:"days"`
+}
+
+// CodeFrequency represents the additions and deletions for a week returned by GitHub.
+// GitHub sends these as `[week, additions, deletions]` with deletions as a negative number.
+// We unmarshal deletions as a positive number to match Week.
+type CodeFrequency struct {
+	WeekBeginning int64
+	Additions     int
+	Deletions     int
+}
+
+// UnmarshalJSON unmarshals the `[week, additions, deletions]` array sent by GitHub
+func (cf *CodeFrequency) UnmarshalJSON(b []byte) error {
+	var raw []int64
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 3 {
+		return errors.Errorf("expected 3 values for code frequency but got %d", len(raw))
+	}
+	cf.WeekBeginning, cf.Additions, cf.Deletions = raw[0], int(raw[1]), -int(raw[2])
+	return nil
+}
+
+// Participation represents the weekly commit counts returned by GitHub for the last 52 weeks.
+// The oldest week is first. Owner is the commits made by the repo owner and All includes the owner.
+type Participation struct {
+	All   []int `json:"all"`
+	Owner []int `json:"owner"`
+}
+
+// PunchCard represents the commits for an hour of a day returned by GitHub.
+// GitHub sends these as `[day, hour, commits]`. Day 0 is Sunday.
+type PunchCard struct {
+	Day     int
+	Hour    int
+	Commits int
+}
+
+// UnmarshalJSON unmarshals the `[day, hour, commits]` array sent by GitHub
+func (pc *PunchCard) UnmarshalJSON(b []byte) error {
+	var raw []int
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 3 {
+		return errors.Errorf("expected 3 values for punch card but got %d", len(raw))
+	}
+	pc.Day, pc.Hour, pc.Commits = raw[0], raw[1], raw[2]
+	return nil
+}
+
+// ListCommitActivity will call the GitHub API for the given repo (owner/name) and return the
+// commits per day for the last year, grouped by week.
+//
+// If GitHub hasn't computed the stats yet (202) the request is retried according to c.Poll.
+func (c Client) ListCommitActivity(ctx context.Context, repoOwner, repoName string) (*[]CommitActivity, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s/stats/commit_activity", c.BaseURL, repoOwner, repoName)
+
+	var res []CommitActivity
+	err := c.poll(ctx, "ListCommitActivity", func(ctx context.Context) error {
+		return c.getStats(ctx, "ListCommitActivity", u, &res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ListCodeFrequency will call the GitHub API for the given repo (owner/name) and return the
+// additions and deletions per week.
+//
+// If GitHub hasn't computed the stats yet (202) the request is retried according to c.Poll.
+func (c Client) ListCodeFrequency(ctx context.Context, repoOwner, repoName string) (*[]CodeFrequency, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s/stats/code_frequency", c.BaseURL, repoOwner, repoName)
+
+	var res []CodeFrequency
+	err := c.poll(ctx, "ListCodeFrequency", func(ctx context.Context) error {
+		return c.getStats(ctx, "ListCodeFrequency", u, &res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetParticipation will call the GitHub API for the given repo (owner/name) and return the
+// weekly commit counts of the owner and everyone for the last 52 weeks.
+//
+// If GitHub hasn't computed the stats yet (202) the request is retried according to c.Poll.
+func (c Client) GetParticipation(ctx context.Context, repoOwner, repoName string) (*Participation, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s/stats/participation", c.BaseURL, repoOwner, repoName)
+
+	var res Participation
+	err := c.poll(ctx, "GetParticipation", func(ctx context.Context) error {
+		return c.getStats(ctx, "GetParticipation", u, &res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ListPunchCard will call the GitHub API for the given repo (owner/name) and return the
+// number of commits per hour of each day of the week.
+//
+// If GitHub hasn't computed the stats yet (202) the request is retried according to c.Poll.
+func (c Client) ListPunchCard(ctx context.Context, repoOwner, repoName string) (*[]PunchCard, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s/stats/punch_card", c.BaseURL, repoOwner, repoName)
+
+	var res []PunchCard
+	err := c.poll(ctx, "ListPunchCard", func(ctx context.Context) error {
+		return c.getStats(ctx, "ListPunchCard", u, &res)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
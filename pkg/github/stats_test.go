@@ -0,0 +1,121 @@
+package github_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+	"github.com/pkg/errors"
+)
+
+func TestRepoStats(t *testing.T) {
+	ts := []struct {
+		Name      string
+		Endpoint  string
+		RespBody  string
+		Call      func(c github.Client) (interface{}, error)
+		ExpectRes interface{}
+	}{
+		{
+			Name:     "Commit Activity",
+			Endpoint: "commit_activity",
+			RespBody: `[{"days":[0,3,26,20,39,1,0],"total":89,"week":1336280400}]`,
+			Call: func(c github.Client) (interface{}, error) {
+				return c.ListCommitActivity(context.Background(), "repo-owner", "repo-name")
+			},
+			ExpectRes: &[]github.CommitActivity{
+				{WeekBeginning: 1336280400, Total: 89, Days: []int{0, 3, 26, 20, 39, 1, 0}},
+			},
+		},
+		{
+			Name:     "Code Frequency",
+			Endpoint: "code_frequency",
+			RespBody: `[[1302998400,1124,-435],[1303603200,0,0]]`,
+			Call: func(c github.Client) (interface{}, error) {
+				return c.ListCodeFrequency(context.Background(), "repo-owner", "repo-name")
+			},
+			ExpectRes: &[]github.CodeFrequency{
+				{WeekBeginning: 1302998400, Additions: 1124, Deletions: 435},
+				{WeekBeginning: 1303603200},
+			},
+		},
+		{
+			Name:     "Participation",
+			Endpoint: "participation",
+			RespBody: `{"all":[11,21,15],"owner":[3,2,3]}`,
+			Call: func(c github.Client) (interface{}, error) {
+				return c.GetParticipation(context.Background(), "repo-owner", "repo-name")
+			},
+			ExpectRes: &github.Participation{All: []int{11, 21, 15}, Owner: []int{3, 2, 3}},
+		},
+		{
+			Name:     "Punch Card",
+			Endpoint: "punch_card",
+			RespBody: `[[0,0,5],[0,1,43]]`,
+			Call: func(c github.Client) (interface{}, error) {
+				return c.ListPunchCard(context.Background(), "repo-owner", "repo-name")
+			},
+			ExpectRes: &[]github.PunchCard{
+				{Day: 0, Hour: 0, Commits: 5},
+				{Day: 0, Hour: 1, Commits: 43},
+			},
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			reqs := 0
+			mockHandler := func(w http.ResponseWriter, r *http.Request) {
+				wantURL := fmt.Sprintf("/repos/repo-owner/repo-name/stats/%s", tc.Endpoint)
+				if r.RequestURI != wantURL {
+					t.Errorf("%s:\n\nhave request url:\n%+v\n\nwant request url:\n%+v", tc.Name, r.RequestURI, wantURL)
+				}
+				// every endpoint should handle GitHub computing the stats
+				reqs++
+				if reqs == 1 {
+					w.WriteHeader(http.StatusAccepted)
+					return
+				}
+				fmt.Fprint(w, tc.RespBody)
+			}
+			mockServer := httptest.NewServer(http.HandlerFunc(mockHandler))
+			defer mockServer.Close()
+
+			client := github.Client{BaseURL: mockServer.URL}
+			_, err := tc.Call(client)
+			if errors.Cause(err) != github.ErrStatsPending {
+				t.Fatalf("%s: have error %v want ErrStatsPending", tc.Name, err)
+			}
+
+			client.Poll = github.PollOpts{Interval: time.Millisecond}
+			reqs = 0
+			res, err := tc.Call(client)
+			if err != nil {
+				t.Fatalf("%s: Unexpected Error: %v", tc.Name, err)
+			}
+			if !reflect.DeepEqual(res, tc.ExpectRes) {
+				t.Errorf("%s:\n\nhave result:\n%+v\n\nwant result:\n%+v", tc.Name, res, tc.ExpectRes)
+			}
+		})
+	}
+}
+
+func TestRepoStatsBadData(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[[1,2]]`)
+	}))
+	defer mockServer.Close()
+
+	client := github.Client{BaseURL: mockServer.URL}
+	if _, err := client.ListCodeFrequency(context.Background(), "repo-owner", "repo-name"); err == nil {
+		t.Error("ListCodeFrequency: Expected error but received nil")
+	}
+	if _, err := client.ListPunchCard(context.Background(), "repo-owner", "repo-name"); err == nil {
+		t.Error("ListPunchCard: Expected error but received nil")
+	}
+}
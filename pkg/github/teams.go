@@ -0,0 +1,32 @@
+package github
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListTeamMembers will call the GitHub API and return the members of the given team of the
+// organisation. team is the team's slug e.g. "core-devs" for a team named "Core Devs".
+// Members of child teams are included. Every page of results is fetched.
+// The client needs read:org access to see the members of a secret team.
+func (c Client) ListTeamMembers(ctx context.Context, org, team string) (*[]Author, error) {
+	u := fmt.Sprintf("%s/orgs/%s/teams/%s/members?per_page=100", c.BaseURL, org, team)
+
+	res := []Author{}
+	for u != "" {
+		var page []Author
+		var next string
+		err := c.poll(ctx, "ListTeamMembers", func(ctx context.Context) error {
+			h, err := c.get(ctx, "ListTeamMembers", u, &page)
+			next = nextPage(h)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, page...)
+		u = next
+	}
+
+	return &res, nil
+}
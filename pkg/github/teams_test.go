@@ -0,0 +1,43 @@
+package github_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+)
+
+func TestListTeamMembers(t *testing.T) {
+	var mockServer *httptest.Server
+	mockHandler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/test-org/teams/test-team/members" {
+			t.Errorf("ListTeamMembers: unexpected request path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/test-org/teams/test-team/members?per_page=100&page=2>; rel="next"`, mockServer.URL))
+			fmt.Fprint(w, `[{"login":"Luke-Davies","type":"User"}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"login":"Ron-Swanson","type":"User"}]`)
+	}
+	mockServer = httptest.NewServer(http.HandlerFunc(mockHandler))
+	defer mockServer.Close()
+
+	client := github.Client{BaseURL: mockServer.URL}
+	res, err := client.ListTeamMembers(context.Background(), "test-org", "test-team")
+	if err != nil {
+		t.Fatalf("ListTeamMembers: Unexpected Error: %v", err)
+	}
+
+	want := &[]github.Author{
+		{Login: "Luke-Davies", Type: "User"},
+		{Login: "Ron-Swanson", Type: "User"},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("ListTeamMembers:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, want)
+	}
+}
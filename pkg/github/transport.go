@@ -0,0 +1,52 @@
+package github
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// TLSOpts contains the TLS options needed to talk to some GitHub Enterprise servers
+// - CAFile: PEM bundle of extra CAs to trust, on top of the system ones. e.g. an internal company CA.
+// - CertFile & KeyFile: PEM client certificate and key, for servers that require mutual TLS.
+type TLSOpts struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// NewTransport returns a clone of http.DefaultTransport, so with the same defaults including HTTP/2,
+// configured with the given TLS options.
+func NewTransport(opts TLSOpts) (*http.Transport, error) {
+	cfg := &tls.Config{}
+
+	if opts.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := ioutil.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "[NewTransport] could not read CA file")
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("[NewTransport] no certificates found in CA file: %s", opts.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "[NewTransport] could not load client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.TLSClientConfig = cfg
+	return tr, nil
+}
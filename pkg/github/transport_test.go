@@ -0,0 +1,95 @@
+package github_test
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+)
+
+func TestNewTransport(t *testing.T) {
+	var haveProto string
+	mockServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		haveProto = r.Proto
+		fmt.Fprint(w, listContributorStatsTestResp)
+	}))
+	mockServer.EnableHTTP2 = true
+	mockServer.StartTLS()
+	defer mockServer.Close()
+
+	dir, err := ioutil.TempDir("", "gh-contrib-stats")
+	if err != nil {
+		t.Fatal("TestNewTransport: Something went wrong creating a temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: mockServer.Certificate().Raw})
+	if err := ioutil.WriteFile(caFile, caPEM, 0600); err != nil {
+		t.Fatal("TestNewTransport: Something went wrong writing the CA file")
+	}
+	notPEMFile := filepath.Join(dir, "not.pem")
+	if err := ioutil.WriteFile(notPEMFile, []byte("blam"), 0600); err != nil {
+		t.Fatal("TestNewTransport: Something went wrong writing a file")
+	}
+
+	ts := []struct {
+		Name            string
+		Opts            github.TLSOpts
+		ExpectOptsError bool
+		ExpectReqError  bool
+	}{
+		{
+			Name: "Custom CA",
+			Opts: github.TLSOpts{CAFile: caFile},
+		},
+		{
+			Name:           "Untrusted",
+			Opts:           github.TLSOpts{},
+			ExpectReqError: true,
+		},
+		{
+			Name:            "Missing CA File",
+			Opts:            github.TLSOpts{CAFile: filepath.Join(dir, "missing.pem")},
+			ExpectOptsError: true,
+		},
+		{
+			Name:            "Bad CA File",
+			Opts:            github.TLSOpts{CAFile: notPEMFile},
+			ExpectOptsError: true,
+		},
+		{
+			Name:            "Client Cert Without Key",
+			Opts:            github.TLSOpts{CertFile: caFile},
+			ExpectOptsError: true,
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			tr, err := github.NewTransport(tc.Opts)
+			if (err != nil) != tc.ExpectOptsError {
+				t.Fatalf("NewTransport: have error %v, want error: %t", err, tc.ExpectOptsError)
+			}
+			if err != nil {
+				return
+			}
+
+			client := github.Client{BaseURL: mockServer.URL, HTTPClient: &http.Client{Transport: tr}}
+			_, err = client.ListContributorStats(context.Background(), "repo-owner", "repo-name")
+			if (err != nil) != tc.ExpectReqError {
+				t.Fatalf("ListContributorStats: have error %v, want error: %t", err, tc.ExpectReqError)
+			}
+			if err == nil && haveProto != "HTTP/2.0" {
+				t.Errorf("NewTransport: have protocol %s want HTTP/2.0", haveProto)
+			}
+		})
+	}
+}
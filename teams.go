@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+	"github.com/pkg/errors"
+)
+
+// teamFileTeam is a team in the team file. The file is a YAML or JSON list of them,
+// see the README for an example.
+type teamFileTeam struct {
+	Team    string   `yaml:"team" json:"team"`
+	Members []string `yaml:"members" json:"members"`
+}
+
+// loadTeams returns the members of the teams in the team file, then of the GitHub teams, given by inputs.
+// GitHub teams are named by their slug. Members are mapped to their canonical login with aliases,
+// so that teams can list any of someone's logins.
+func loadTeams(ctx context.Context, client github.Client, inputs processedInputs, aliases app.Aliases) ([]app.TeamMembers, error) {
+	var res []app.TeamMembers
+
+	if inputs.Teams != "" {
+		var teams []teamFileTeam
+		if err := readConfig(inputs.Teams, &teams); err != nil {
+			return nil, errors.Wrap(err, "[loadTeams] could not load team file")
+		}
+		for _, t := range teams {
+			if t.Team == "" {
+				return nil, errors.Errorf("[loadTeams] invalid team file %s: every team needs a name", inputs.Teams)
+			}
+			res = append(res, app.TeamMembers{Team: t.Team, Members: t.Members})
+		}
+	}
+
+	for _, t := range inputs.GitHubTeams {
+		parts := strings.SplitN(t, "/", 2)
+		members, err := client.ListTeamMembers(ctx, parts[0], parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "[loadTeams] could not get the members of %s", t)
+		}
+		tm := app.TeamMembers{Team: parts[1]}
+		for _, m := range *members {
+			tm.Members = append(tm.Members, m.Login)
+		}
+		res = append(res, tm)
+	}
+
+	for _, tm := range res {
+		for i, m := range tm.Members {
+			tm.Members[i] = aliases.Canonical(m)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/luke-davies/gh-contrib-stats/pkg/github"
+)
+
+func TestLoadTeams(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gh-contrib-stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, text string) string {
+		file := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(file, []byte(text), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/test-org/teams/backend/members" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `[{"login": "Luke-Davies", "type": "User"}, {"login": "lknope-corp", "type": "User"}]`)
+	}))
+	defer mockServer.Close()
+	client := github.Client{BaseURL: mockServer.URL}
+
+	aliases, err := app.NewAliases([]app.Person{{Login: "Leslie-Knope", Aliases: []string{"lknope-corp"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := []struct {
+		Name      string
+		Inputs    processedInputs
+		ExpectRes []app.TeamMembers
+		ExpectErr string
+	}{
+		{
+			Name: "File And GitHub",
+			Inputs: processedInputs{
+				Teams:       write("teams.yml", "- team: parks\n  members: [Ron-Swanson, lknope-corp]\n- team: empty\n"),
+				GitHubTeams: []string{"test-org/backend"},
+			},
+			ExpectRes: []app.TeamMembers{
+				{Team: "parks", Members: []string{"Ron-Swanson", "Leslie-Knope"}},
+				{Team: "empty"},
+				{Team: "backend", Members: []string{"Luke-Davies", "Leslie-Knope"}},
+			},
+		},
+		{
+			Name:      "Unnamed Team",
+			Inputs:    processedInputs{Teams: write("unnamed.json", `[{"members": ["Ron-Swanson"]}]`)},
+			ExpectErr: "[loadTeams] invalid team file " + filepath.Join(dir, "unnamed.json") + ": every team needs a name",
+		},
+		{
+			Name:      "Unknown GitHub Team",
+			Inputs:    processedInputs{GitHubTeams: []string{"test-org/frontend"}},
+			ExpectErr: "[loadTeams] could not get the members of test-org/frontend",
+		},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			res, err := loadTeams(context.Background(), client, tc.Inputs, aliases)
+			if tc.ExpectErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.ExpectErr) {
+					t.Fatalf("loadTeams: Have `err`: %v want: %s", err, tc.ExpectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadTeams: Unexpected Error: %v", err)
+			}
+			if !reflect.DeepEqual(res, tc.ExpectRes) {
+				t.Errorf("loadTeams:\n\nhave result:\n%+v\n\nwant result:\n%+v", res, tc.ExpectRes)
+			}
+		})
+	}
+}
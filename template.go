@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/luke-davies/gh-contrib-stats/pkg/app"
+	"github.com/pkg/errors"
+)
+
+// templateFuncs are the helper functions available to --template and --template-string, on top of
+// the text/template builtins. See the README for the data model the templates are executed against (app.Report).
+var templateFuncs = template.FuncMap{
+	"number":   formatNumber,
+	"percent":  formatPercent,
+	"decimal":  formatDecimal,
+	"duration": formatDuration,
+	"date":     func(t time.Time) string { return t.Format("2006-01-02") },
+	"join":     strings.Join,
+	"period":   app.PeriodLabel,
+}
+
+// loadTemplate parses the template given by --template or --template-string.
+// Returns nil if neither was given.
+func loadTemplate(inputs processedInputs) (*template.Template, error) {
+	text := inputs.TemplateString
+	if inputs.Template != "" {
+		b, err := ioutil.ReadFile(inputs.Template)
+		if err != nil {
+			return nil, errors.Wrap(err, "[loadTemplate] could not read template")
+		}
+		text = string(b)
+	} else if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New("report").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "[loadTemplate] invalid template")
+	}
+	return tmpl, nil
+}
+
+// formatNumber formats n with thousands separators e.g. 1,234,567
+func formatNumber(n int) string {
+	s := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + s
+}
+
+// formatPercent formats part as a percentage of total, with one decimal place e.g. 12.5%.
+// It's 0.0% if total is zero.
+func formatPercent(part, total int) string {
+	if total == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
+
+// formatDuration formats d in the largest units that make sense for a date range e.g. "2 weeks 3 days" or "5 hours".
+// Durations under a minute use time.Duration's format.
+func formatDuration(d time.Duration) string {
+	day := 24 * time.Hour
+	units := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"week", 7 * day},
+		{"day", day},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	if d < time.Minute {
+		return sign + d.String()
+	}
+
+	// at most two units e.g. weeks and days, rounded down
+	var parts []string
+	for _, u := range units {
+		if n := int(d / u.d); n > 0 || len(parts) > 0 {
+			if n > 0 {
+				parts = append(parts, plural(n, u.name))
+			}
+			d -= time.Duration(n) * u.d
+			if len(parts) == 2 || (len(parts) == 1 && n == 0) {
+				break
+			}
+		}
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
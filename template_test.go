@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadTemplate(t *testing.T) {
+	text := `{{join .Repos ", "}} {{date .From}} to {{date .To}} ({{duration (.To.Sub .From)}})
+{{$total := .Total}}{{range .Contributors}}{{.Name}}: {{number .Stats.Additions}} ({{percent .Stats.Commits $total.Commits}})
+{{end}}`
+	expect := `test-owner/test-repo, test-owner/other-repo 2018-06-03 to 2018-07-01 (4 weeks)
+test-user-1: 120 (75.0%)
+Knope, Leslie: 5 (25.0%)
+`
+
+	dir, err := ioutil.TempDir("", "gh-contrib-stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "report.tmpl")
+	if err := ioutil.WriteFile(file, []byte(text), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := []struct {
+		Name   string
+		Inputs processedInputs
+	}{
+		{Name: "File", Inputs: processedInputs{Template: file}},
+		{Name: "String", Inputs: processedInputs{TemplateString: text}},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			tmpl, err := loadTemplate(tc.Inputs)
+			if err != nil {
+				t.Fatalf("loadTemplate: Unexpected Error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, testReport); err != nil {
+				t.Fatalf("Execute: Unexpected Error: %v", err)
+			}
+			if buf.String() != expect {
+				t.Errorf("Execute:\n\nhave:\n%s\n\nwant:\n%s", buf.String(), expect)
+			}
+		})
+	}
+}
+
+func TestLoadTemplateErrors(t *testing.T) {
+	if tmpl, err := loadTemplate(processedInputs{}); tmpl != nil || err != nil {
+		t.Errorf("loadTemplate: have %v, %v for no template want nil, nil", tmpl, err)
+	}
+
+	_, err := loadTemplate(processedInputs{TemplateString: "{{range .Contributors}}"})
+	if err == nil || !strings.HasPrefix(err.Error(), "[loadTemplate] invalid template") {
+		t.Errorf("loadTemplate: have error %v for an invalid template", err)
+	}
+
+	_, err = loadTemplate(processedInputs{Template: "does-not-exist.tmpl"})
+	if err == nil || !strings.HasPrefix(err.Error(), "[loadTemplate] could not read template") {
+		t.Errorf("loadTemplate: have error %v for a missing file", err)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	ts := map[int]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567", -12345: "-12,345"}
+	for n, want := range ts {
+		if have := formatNumber(n); have != want {
+			t.Errorf("formatNumber(%d): have %s want %s", n, have, want)
+		}
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	if have := formatPercent(1, 8); have != "12.5%" {
+		t.Errorf("formatPercent: have %s want 12.5%%", have)
+	}
+	if have := formatPercent(1, 0); have != "0.0%" {
+		t.Errorf("formatPercent: have %s want 0.0%% when total is zero", have)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	day := 24 * time.Hour
+	ts := map[time.Duration]string{
+		30 * time.Second:           "30s",
+		5 * time.Minute:            "5 minutes",
+		time.Hour + 30*time.Minute: "1 hour 30 minutes",
+		3 * day:                    "3 days",
+		17*day + 5*time.Hour:       "2 weeks 3 days",
+		14*day + 5*time.Hour:       "2 weeks",
+		7 * day:                    "1 week",
+		-(2*time.Hour + time.Minute + time.Second): "-2 hours 1 minute",
+	}
+	for d, want := range ts {
+		if have := formatDuration(d); have != want {
+			t.Errorf("formatDuration(%s): have %s want %s", d, have, want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// defaultTerminalWidth is used when the width of the terminal can't be found e.g. when the output is piped
+const defaultTerminalWidth = 80
+
+// terminalWidth returns the width of the terminal in columns.
+// The COLUMNS environment variable takes precedence, as most shells set it.
+func terminalWidth() int {
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	return defaultTerminalWidth
+}
+
+// sparkTicks are the characters of a sparkline, from lowest to highest
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a line of block characters, scaled to the largest value.
+// Zero is drawn as a space so that inactive periods stand out.
+// If there are more values than width, neighbouring values are summed so that it fits.
+func sparkline(values []int, width int) string {
+	if width > 0 && len(values) > width {
+		values = bucket(values, width)
+	}
+
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	res := make([]rune, len(values))
+	for i, v := range values {
+		if v <= 0 {
+			res[i] = ' '
+			continue
+		}
+		// any value gets at least the lowest tick
+		res[i] = sparkTicks[(v*len(sparkTicks)-1)/max]
+	}
+	return string(res)
+}
+
+// bucket sums values into n buckets of (nearly) equal size
+func bucket(values []int, n int) []int {
+	res := make([]int, n)
+	for i, v := range values {
+		res[i*n/len(values)] += v
+	}
+	return res
+}
+
+// barEighths are the partial blocks used for the end of a bar, from 1/8 to 7/8 of a full block
+var barEighths = []rune("▏▎▍▌▋▊▉")
+
+// bar draws v as a horizontal bar, where max is width characters long.
+// The end of the bar uses partial blocks for an eighth of a character precision.
+func bar(v, max, width int) string {
+	if v <= 0 || max <= 0 || width <= 0 {
+		return ""
+	}
+	eighths := v * width * 8 / max
+	if eighths == 0 {
+		// any value gets at least a sliver
+		eighths = 1
+	}
+	res := strings.Repeat("█", eighths/8)
+	if rem := eighths % 8; rem > 0 {
+		res += string(barEighths[rem-1])
+	}
+	return res
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	ts := []struct {
+		Name   string
+		Values []int
+		Width  int
+		Expect string
+	}{
+		{Name: "Scaled", Values: []int{0, 1, 4, 8}, Expect: " ▁▄█"},
+		{Name: "All Zero", Values: []int{0, 0}, Expect: "  "},
+		{Name: "Empty", Expect: ""},
+		// summed into [1, 7]
+		{Name: "Too Wide", Values: []int{1, 0, 3, 4}, Width: 2, Expect: "▂█"},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			if have := sparkline(tc.Values, tc.Width); have != tc.Expect {
+				t.Errorf("sparkline: have %q want %q", have, tc.Expect)
+			}
+		})
+	}
+}
+
+func TestBucket(t *testing.T) {
+	have := bucket([]int{1, 2, 3, 4, 5}, 2)
+	if len(have) != 2 || have[0]+have[1] != 15 {
+		t.Errorf("bucket: have %v want 2 buckets summing to 15", have)
+	}
+}
+
+func TestBar(t *testing.T) {
+	ts := []struct {
+		Name   string
+		V      int
+		Max    int
+		Width  int
+		Expect string
+	}{
+		{Name: "Full", V: 10, Max: 10, Width: 4, Expect: "████"},
+		{Name: "Half", V: 5, Max: 10, Width: 4, Expect: "██"},
+		{Name: "Partial", V: 3, Max: 10, Width: 4, Expect: "█▏"},
+		{Name: "Sliver", V: 1, Max: 1000, Width: 4, Expect: "▏"},
+		{Name: "Zero", V: 0, Max: 10, Width: 4, Expect: ""},
+	}
+
+	for _, tc := range ts {
+		t.Run(tc.Name, func(t *testing.T) {
+			if have := bar(tc.V, tc.Max, tc.Width); have != tc.Expect {
+				t.Errorf("bar: have %q want %q", have, tc.Expect)
+			}
+		})
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	defer os.Setenv("COLUMNS", os.Getenv("COLUMNS"))
+
+	os.Setenv("COLUMNS", "132")
+	if have := terminalWidth(); have != 132 {
+		t.Errorf("terminalWidth: have %d want 132 from $COLUMNS", have)
+	}
+
+	// the tests' stdout isn't a terminal
+	os.Setenv("COLUMNS", "")
+	if have := terminalWidth(); have <= 0 {
+		t.Errorf("terminalWidth: have %d want a positive width", have)
+	}
+}